@@ -0,0 +1,86 @@
+package robust
+
+import "math"
+
+// Orient2Batch stores `Orient2` for every triple of points in the flat
+// `[]float64` buffer pts of 6 values per element, i.e. ax, ay, bx, by, cx,
+// cy, into out, which must hold at least len(pts)/6 values.
+//
+// The stage A filter runs over the whole batch first, on amd64 with SIMD
+// instructions when the CPU supports them, producing a mask of the
+// elements whose sign it certifies. Only the others are evaluated with
+// the adaptive implementation. The results are identical to calling
+// `Orient2` for each element.
+func Orient2Batch(pts []float64, out []float64) {
+	n := len(pts) / 6
+	out = out[:n]
+	mask := make([]uint64, (n+63)/64)
+	k := orient2BatchA(pts[:6*n], out, mask)
+	for i := 0; i < n; i++ {
+		if i >= k || mask[i/64]&(1<<uint(i%64)) == 0 {
+			p := pts[6*i : 6*i+6]
+			out[i] = Orient2(p[0:2], p[2:4], p[4:6])
+		}
+	}
+}
+
+// Orient3Batch stores `Orient3` for every quadruple of points in the flat
+// `[]float64` buffer pts of 12 values per element into out, which must
+// hold at least len(pts)/12 values. It's evaluated like `Orient2Batch`
+// and the results are identical to calling `Orient3` for each element.
+func Orient3Batch(pts []float64, out []float64) {
+	n := len(pts) / 12
+	out = out[:n]
+	mask := make([]uint64, (n+63)/64)
+	k := orient3BatchA(pts[:12*n], out, mask)
+	for i := 0; i < n; i++ {
+		if i >= k || mask[i/64]&(1<<uint(i%64)) == 0 {
+			p := pts[12*i : 12*i+12]
+			out[i] = Orient3(p[0:3], p[3:6], p[6:9], p[9:12])
+		}
+	}
+}
+
+// The batch filters store the rounded determinant of the leading elements
+// of pts in out and set the bits of mask for the ones whose sign is
+// certain, returning the number of elements processed. The mask must be
+// zeroed. Architectures may replace them with vectorized versions that
+// leave a few trailing elements to the caller.
+var (
+	orient2BatchA = orient2BatchGeneric
+	orient3BatchA = orient3BatchGeneric
+)
+
+// orient2BatchGeneric is the filter of `orient2A` with its cases folded
+// into one comparison: when detleft and detright differ in sign or either
+// is zero, |det| is |detleft| + |detright| rounded, which is never below
+// the bound.
+func orient2BatchGeneric(pts, out []float64, mask []uint64) int {
+	for i := range out {
+		p := pts[6*i : 6*i+6]
+		detleft := float64((p[0] - p[4]) * (p[3] - p[5]))
+		detright := float64((p[1] - p[5]) * (p[2] - p[4]))
+		det := detleft - detright
+		out[i] = det
+		if ccwerrboundA*(math.Abs(detleft)+math.Abs(detright)) <= math.Abs(det) {
+			mask[i/64] |= 1 << uint(i%64)
+		}
+	}
+	return len(out)
+}
+
+func orient3BatchGeneric(pts, out []float64, mask []uint64) int {
+	for i := range out {
+		p := pts[12*i : 12*i+12]
+		det, _, ok := orient3A(
+			p[0]-p[9], p[3]-p[9], p[6]-p[9],
+			p[1]-p[10], p[4]-p[10], p[7]-p[10],
+			p[2]-p[11], p[5]-p[11], p[8]-p[11],
+		)
+		out[i] = det
+		if ok {
+			mask[i/64] |= 1 << uint(i%64)
+		}
+	}
+	return len(out)
+}
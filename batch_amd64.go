@@ -0,0 +1,22 @@
+package robust
+
+import "neilpa.me/cgo-shewchuk-robust/internal/batch"
+
+func init() {
+	// The vectorized filters process whole groups of lanes, leaving the
+	// rest to the caller.
+	orient2BatchA = func(pts, out []float64, mask []uint64) int {
+		n := len(out) &^ (batch.Lanes - 1)
+		if n > 0 {
+			batch.Orient2(pts[:6*n], out[:n], mask, ccwerrboundA)
+		}
+		return n
+	}
+	orient3BatchA = func(pts, out []float64, mask []uint64) int {
+		n := len(out) &^ (batch.Lanes - 1)
+		if n > 0 {
+			batch.Orient3(pts[:12*n], out[:n], mask, o3derrboundA)
+		}
+		return n
+	}
+}
@@ -0,0 +1,132 @@
+package robust_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// batchModes runs fn with the default batch filters, which are
+// vectorized where supported, and with the generic ones.
+func batchModes(t testing.TB, fn func(t testing.TB)) {
+	fn(t)
+	restore := robust.UseGenericBatch()
+	defer restore()
+	fn(t)
+}
+
+// randomPoints returns n values, half of them snapped to a coarse grid so
+// that many elements are degenerate.
+func randomPoints(rng *rand.Rand, n int) []float64 {
+	pts := make([]float64, n)
+	for i := range pts {
+		pts[i] = rng.Float64()
+		if rng.Intn(2) == 0 {
+			pts[i] = math.Floor(pts[i] * 4)
+		}
+	}
+	return pts
+}
+
+// sameBatch reports whether a batch result matches the predicate, which
+// only agree on the sign when the predicate's filter is fused.
+func sameBatch(got, want float64) bool {
+	if robust.FusedFilters {
+		return sign(got) == sign(want)
+	}
+	return math.Float64bits(got) == math.Float64bits(want)
+}
+
+func Test_Orient2Batch(t *testing.T) {
+	var fixtures []float64
+	for _, tt := range loadCases(t, "orient2.txt", 6) {
+		fixtures = append(fixtures, tt.args...)
+	}
+	random := randomPoints(rand.New(rand.NewSource(1)), 6*1001)
+
+	batchModes(t, func(t testing.TB) {
+		for _, pts := range [][]float64{fixtures, random, random[:6*3], nil} {
+			out := make([]float64, len(pts)/6)
+			robust.Orient2Batch(pts, out)
+			for i := range out {
+				p := pts[6*i : 6*i+6]
+				want := robust.Orient2(p[0:2], p[2:4], p[4:6])
+				if !sameBatch(out[i], want) {
+					t.Fatalf("element %d: want %g; got %g", i, want, out[i])
+				}
+			}
+		}
+	})
+}
+
+func Test_Orient3Batch(t *testing.T) {
+	var fixtures []float64
+	for _, tt := range loadCases(t, "orient3.txt", 12) {
+		fixtures = append(fixtures, tt.args...)
+	}
+	random := randomPoints(rand.New(rand.NewSource(1)), 12*1001)
+
+	batchModes(t, func(t testing.TB) {
+		for _, pts := range [][]float64{fixtures, random, random[:12*3], nil} {
+			out := make([]float64, len(pts)/12)
+			robust.Orient3Batch(pts, out)
+			for i := range out {
+				p := pts[12*i : 12*i+12]
+				want := robust.Orient3(p[0:3], p[3:6], p[6:9], p[9:12])
+				if !sameBatch(out[i], want) {
+					t.Fatalf("element %d: want %g; got %g", i, want, out[i])
+				}
+			}
+		}
+	})
+}
+
+func Benchmark_Orient2Batch(b *testing.B) {
+	pts := randomPoints(rand.New(rand.NewSource(1)), 6*4096)
+	out := make([]float64, len(pts)/6)
+	b.Run("loop", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for i := range out {
+				p := pts[6*i : 6*i+6]
+				out[i] = robust.Orient2(p[0:2], p[2:4], p[4:6])
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			robust.Orient2Batch(pts, out)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		defer robust.UseGenericBatch()()
+		for n := 0; n < b.N; n++ {
+			robust.Orient2Batch(pts, out)
+		}
+	})
+}
+
+func Benchmark_Orient3Batch(b *testing.B) {
+	pts := randomPoints(rand.New(rand.NewSource(1)), 12*4096)
+	out := make([]float64, len(pts)/12)
+	b.Run("loop", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for i := range out {
+				p := pts[12*i : 12*i+12]
+				out[i] = robust.Orient3(p[0:3], p[3:6], p[6:9], p[9:12])
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			robust.Orient3Batch(pts, out)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		defer robust.UseGenericBatch()()
+		for n := 0; n < b.N; n++ {
+			robust.Orient3Batch(pts, out)
+		}
+	})
+}
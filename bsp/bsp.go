@@ -0,0 +1,337 @@
+// Package bsp builds binary space partitioning trees of convex polygons
+// as a base for constructive solid geometry. Every plane is defined by
+// three points and every front, back or on decision is the exact sign of
+// `robust.Orient3`, so classification never depends on a tolerance.
+//
+// Splitting a polygon constructs new vertices, which are rounded. The
+// cut of an edge only depends on its endpoints and the plane, not on the
+// polygon or the direction it's traversed in, so polygons sharing an edge
+// are split at bit-identical points and no cracks open between them. A
+// polygon keeps the exact plane of its source after splitting.
+package bsp
+
+import robust "neilpa.me/cgo-shewchuk-robust"
+
+// Side classifies a point or polygon relative to a plane.
+type Side int
+
+const (
+	// Coplanar means the point or every vertex lies on the plane.
+	Coplanar Side = iota
+	// Front means above the plane following the conventions of
+	// `robust.Orient3`, i.e. on the side from which the plane points
+	// appear counterclockwise.
+	Front
+	// Back means below the plane.
+	Back
+	// Spanning means the polygon has vertices both in front and in back.
+	Spanning
+)
+
+func (s Side) String() string {
+	switch s {
+	case Coplanar:
+		return "coplanar"
+	case Front:
+		return "front"
+	case Back:
+		return "back"
+	case Spanning:
+		return "spanning"
+	}
+	return "unknown"
+}
+
+// Plane is the oriented plane through three points. Its front is the
+// side from which they appear counterclockwise.
+type Plane [3][]float64
+
+// Side classifies the point p, which must contain at least 3 values.
+func (pl Plane) Side(p []float64) Side {
+	det := robust.Orient3(pl[0], pl[1], pl[2], p)
+	switch {
+	case det < 0:
+		return Front
+	case det > 0:
+		return Back
+	}
+	return Coplanar
+}
+
+// Flip returns the plane with front and back swapped.
+func (pl Plane) Flip() Plane {
+	return Plane{pl[0], pl[2], pl[1]}
+}
+
+// Polygon is a convex planar polygon whose vertices are counterclockwise
+// seen from the front of its plane.
+type Polygon struct {
+	Vertices [][]float64
+	Plane    Plane
+}
+
+// NewPolygon returns the polygon of the vertices with the plane through
+// the first three, which must not be collinear.
+func NewPolygon(vertices ...[]float64) Polygon {
+	return Polygon{
+		Vertices: vertices,
+		Plane:    Plane{vertices[0], vertices[1], vertices[2]},
+	}
+}
+
+// FromMesh returns a triangle polygon for every face of the mesh given
+// as a flat `[]float64` vertex buffer of XYZ triples and a flat `[]int32`
+// index buffer of vertex triples per face.
+func FromMesh(verts []float64, tris []int32) []Polygon {
+	polys := make([]Polygon, 0, len(tris)/3)
+	for f := 0; f+2 < len(tris); f += 3 {
+		a, b, c := tris[f], tris[f+1], tris[f+2]
+		polys = append(polys, NewPolygon(verts[3*a:3*a+3], verts[3*b:3*b+3], verts[3*c:3*c+3]))
+	}
+	return polys
+}
+
+// Flip returns the polygon facing the other way.
+func (p Polygon) Flip() Polygon {
+	n := len(p.Vertices)
+	vertices := make([][]float64, n)
+	for i, v := range p.Vertices {
+		vertices[n-1-i] = v
+	}
+	return Polygon{Vertices: vertices, Plane: p.Plane.Flip()}
+}
+
+// Classify returns the side of the polygon relative to the plane.
+func (pl Plane) Classify(p Polygon) Side {
+	side := Coplanar
+	for _, v := range p.Vertices {
+		s := pl.Side(v)
+		if s == Coplanar || s == side {
+			continue
+		}
+		if side != Coplanar {
+			return Spanning
+		}
+		side = s
+	}
+	return side
+}
+
+// Split classifies the polygon and returns its parts in front of and in
+// back of the plane, either of which may be nil. A coplanar polygon is
+// returned as the front part when it faces the same way as the plane and
+// as the back part otherwise. Vertices on the plane go to both parts of a
+// spanning polygon.
+func (pl Plane) Split(p Polygon) (side Side, front, back *Polygon) {
+	sides := make([]Side, len(p.Vertices))
+	side = Coplanar
+	for i, v := range p.Vertices {
+		sides[i] = pl.Side(v)
+		if sides[i] != Coplanar && sides[i] != side {
+			if side == Coplanar {
+				side = sides[i]
+			} else {
+				side = Spanning
+			}
+		}
+	}
+
+	switch side {
+	case Coplanar:
+		if pl.facing(p.Plane) {
+			return side, &p, nil
+		}
+		return side, nil, &p
+	case Front:
+		return side, &p, nil
+	case Back:
+		return side, nil, &p
+	}
+
+	f := &Polygon{Plane: p.Plane}
+	b := &Polygon{Plane: p.Plane}
+	for i, v := range p.Vertices {
+		j := (i + 1) % len(p.Vertices)
+		if sides[i] != Back {
+			f.Vertices = append(f.Vertices, v)
+		}
+		if sides[i] != Front {
+			b.Vertices = append(b.Vertices, v)
+		}
+		if sides[i]|sides[j] == Front|Back {
+			x := pl.cut(v, p.Vertices[j])
+			f.Vertices = append(f.Vertices, x)
+			b.Vertices = append(b.Vertices, x)
+		}
+	}
+	return side, f, b
+}
+
+// facing reports whether the coplanar plane o faces the same way, by
+// comparing their orientations on a coordinate projection where pl
+// doesn't degenerate.
+func (pl Plane) facing(o Plane) bool {
+	for axis := 2; axis >= 0; axis-- {
+		i, j := (axis+1)%3, (axis+2)%3
+		s := robust.Orient2(
+			[]float64{pl[0][i], pl[0][j]},
+			[]float64{pl[1][i], pl[1][j]},
+			[]float64{pl[2][i], pl[2][j]},
+		)
+		if s == 0 {
+			continue
+		}
+		t := robust.Orient2(
+			[]float64{o[0][i], o[0][j]},
+			[]float64{o[1][i], o[1][j]},
+			[]float64{o[2][i], o[2][j]},
+		)
+		return (s > 0) == (t > 0)
+	}
+	return true
+}
+
+// cut returns the point where the segment ab, whose endpoints are on
+// opposite sides, crosses the plane. The endpoints are put in
+// lexicographic order first so that the result doesn't depend on the
+// direction of the edge.
+func (pl Plane) cut(a, b []float64) []float64 {
+	for k := 0; k < 3; k++ {
+		if a[k] != b[k] {
+			if a[k] > b[k] {
+				a, b = b, a
+			}
+			break
+		}
+	}
+	da := robust.Orient3(pl[0], pl[1], pl[2], a)
+	db := robust.Orient3(pl[0], pl[1], pl[2], b)
+	t := da / (da - db)
+	return []float64{
+		a[0] + t*(b[0]-a[0]),
+		a[1] + t*(b[1]-a[1]),
+		a[2] + t*(b[2]-a[2]),
+	}
+}
+
+// Node is a node of a BSP tree. A nil *Node is a valid empty tree.
+type Node struct {
+	// Plane is the splitting plane of the node.
+	Plane Plane
+	// Polygons holds the polygons lying in the plane, facing either way.
+	Polygons []Polygon
+	// Front and Back are the subtrees on each side of the plane.
+	Front, Back *Node
+}
+
+// Build returns the BSP tree of the polygons, or nil if there are none.
+// Each node splits by the plane of the first polygon reaching it.
+func Build(polys []Polygon) *Node {
+	if len(polys) == 0 {
+		return nil
+	}
+	n := &Node{Plane: polys[0].Plane}
+	n.Add(polys)
+	return n
+}
+
+// Add inserts the polygons into the non-nil tree, splitting them by the
+// existing planes and growing new leaves where needed.
+func (n *Node) Add(polys []Polygon) {
+	var front, back []Polygon
+	for _, p := range polys {
+		side, f, b := n.Plane.Split(p)
+		switch {
+		case side == Coplanar && f != nil:
+			n.Polygons = append(n.Polygons, *f)
+		case side == Coplanar:
+			n.Polygons = append(n.Polygons, *b)
+		default:
+			if f != nil {
+				front = append(front, *f)
+			}
+			if b != nil {
+				back = append(back, *b)
+			}
+		}
+	}
+	if len(front) > 0 {
+		if n.Front == nil {
+			n.Front = Build(front)
+		} else {
+			n.Front.Add(front)
+		}
+	}
+	if len(back) > 0 {
+		if n.Back == nil {
+			n.Back = Build(back)
+		} else {
+			n.Back.Add(back)
+		}
+	}
+}
+
+// AllPolygons returns every polygon in the tree.
+func (n *Node) AllPolygons() []Polygon {
+	if n == nil {
+		return nil
+	}
+	polys := append([]Polygon(nil), n.Polygons...)
+	polys = append(polys, n.Front.AllPolygons()...)
+	return append(polys, n.Back.AllPolygons()...)
+}
+
+// Invert turns the solid bounded by the tree inside out, swapping the
+// front and back of every plane and polygon.
+func (n *Node) Invert() {
+	if n == nil {
+		return
+	}
+	n.Plane = n.Plane.Flip()
+	for i, p := range n.Polygons {
+		n.Polygons[i] = p.Flip()
+	}
+	n.Front.Invert()
+	n.Back.Invert()
+	n.Front, n.Back = n.Back, n.Front
+}
+
+// ClipPolygons returns the parts of the polygons outside the solid
+// bounded by the tree, where outside is in front of the leaf planes.
+// Parts lying on the boundary are kept when they face the same way as
+// it. An empty tree keeps everything.
+func (n *Node) ClipPolygons(polys []Polygon) []Polygon {
+	if n == nil {
+		return append([]Polygon(nil), polys...)
+	}
+	var front, back []Polygon
+	for _, p := range polys {
+		_, f, b := n.Plane.Split(p)
+		if f != nil {
+			front = append(front, *f)
+		}
+		if b != nil {
+			back = append(back, *b)
+		}
+	}
+	if n.Front != nil {
+		front = n.Front.ClipPolygons(front)
+	}
+	if n.Back != nil {
+		back = n.Back.ClipPolygons(back)
+	} else {
+		back = nil
+	}
+	return append(front, back...)
+}
+
+// ClipTo removes the parts of every polygon in the tree that are inside
+// the solid bounded by the other tree.
+func (n *Node) ClipTo(o *Node) {
+	if n == nil {
+		return
+	}
+	n.Polygons = o.ClipPolygons(n.Polygons)
+	n.Front.ClipTo(o)
+	n.Back.ClipTo(o)
+}
@@ -0,0 +1,170 @@
+package bsp_test
+
+import (
+	"math"
+	"testing"
+
+	"neilpa.me/cgo-shewchuk-robust/bsp"
+)
+
+// box returns the outward facing polygons of an axis aligned box.
+func box(lo, hi [3]float64) []bsp.Polygon {
+	corner := func(i int) []float64 {
+		v := []float64{lo[0], lo[1], lo[2]}
+		for k := 0; k < 3; k++ {
+			if i&(1<<uint(k)) != 0 {
+				v[k] = hi[k]
+			}
+		}
+		return v
+	}
+	var polys []bsp.Polygon
+	for _, q := range [][4]int{
+		{0, 2, 3, 1}, {4, 5, 7, 6},
+		{0, 1, 5, 4}, {2, 6, 7, 3},
+		{0, 4, 6, 2}, {1, 3, 7, 5},
+	} {
+		polys = append(polys, bsp.NewPolygon(corner(q[0]), corner(q[1]), corner(q[2]), corner(q[3])))
+	}
+	return polys
+}
+
+// area returns the total area of the polygons.
+func area(polys []bsp.Polygon) float64 {
+	total := 0.0
+	for _, p := range polys {
+		var n [3]float64
+		for i, a := range p.Vertices {
+			b := p.Vertices[(i+1)%len(p.Vertices)]
+			n[0] += (a[1] - b[1]) * (a[2] + b[2])
+			n[1] += (a[2] - b[2]) * (a[0] + b[0])
+			n[2] += (a[0] - b[0]) * (a[1] + b[1])
+		}
+		total += math.Sqrt(n[0]*n[0]+n[1]*n[1]+n[2]*n[2]) / 2
+	}
+	return total
+}
+
+func Test_PlaneSide(t *testing.T) {
+	pl := bsp.Plane{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	tiny := math.Ldexp(1, -54)
+	tests := []struct {
+		p    []float64
+		want bsp.Side
+	}{
+		{[]float64{0.5, 0.25, 0.25}, bsp.Coplanar},
+		{[]float64{0.5, 0.25, 0.25 + tiny}, bsp.Front},
+		{[]float64{0.5, 0.25, 0.25 - tiny/2}, bsp.Back},
+		{[]float64{0, 0, 0}, bsp.Back},
+	}
+	for _, tt := range tests {
+		if got := pl.Side(tt.p); got != tt.want {
+			t.Errorf("%v want: %v; got: %v", tt.p, tt.want, got)
+		}
+		if got := pl.Flip().Side(tt.p); tt.want != bsp.Coplanar && got == tt.want {
+			t.Errorf("%v flipped got: %v", tt.p, got)
+		}
+	}
+}
+
+func Test_Split(t *testing.T) {
+	pl := bsp.Plane{{1, 0, 0}, {1, 1, 0}, {1, 0, 1}}
+	tri := bsp.NewPolygon([]float64{0, 0, 0}, []float64{2, 0, 0}, []float64{1, 2, 0})
+	if got := pl.Classify(tri); got != bsp.Spanning {
+		t.Fatalf("classify want: spanning; got: %v", got)
+	}
+	side, front, back := pl.Split(tri)
+	if side != bsp.Spanning || front == nil || back == nil {
+		t.Fatalf("want: spanning; got: %v %v %v", side, front, back)
+	}
+	if len(front.Vertices) != 3 || len(back.Vertices) != 3 {
+		t.Errorf("want: two triangles; got: %v %v", front.Vertices, back.Vertices)
+	}
+	for _, p := range []*bsp.Polygon{front, back} {
+		if &p.Plane[0][0] != &tri.Plane[0][0] {
+			t.Errorf("plane not kept: %v", p.Plane)
+		}
+	}
+	if a := area([]bsp.Polygon{*front}); a != 1 {
+		t.Errorf("front area want: 1; got: %g", a)
+	}
+
+	// Coplanar polygons go by facing
+	on := bsp.NewPolygon([]float64{1, 0, 0}, []float64{1, 1, 0}, []float64{1, 1, 1})
+	if side, f, b := pl.Split(on); side != bsp.Coplanar || f == nil || b != nil {
+		t.Errorf("same facing want: front; got: %v %v %v", side, f, b)
+	}
+	if side, f, b := pl.Split(on.Flip()); side != bsp.Coplanar || f != nil || b == nil {
+		t.Errorf("opposite facing want: back; got: %v %v %v", side, f, b)
+	}
+}
+
+func Test_SplitSharedEdge(t *testing.T) {
+	// Two triangles traverse the edge ab in opposite directions and must
+	// be cut at the same point.
+	a, b := []float64{0.1, -0.7, 0.3}, []float64{0.9, 1.3, -0.2}
+	p := bsp.NewPolygon(a, b, []float64{-0.4, 0.6, 0.5})
+	q := bsp.NewPolygon(b, a, []float64{1.1, -0.3, 0.7})
+	pl := bsp.Plane{{0.33, 0.17, 0}, {0.41, 0.29, 1}, {0.2, 0.9, 0.1}}
+
+	if sa, sb := pl.Side(a), pl.Side(b); sa == bsp.Coplanar || sa == sb {
+		t.Fatalf("plane doesn't cross the edge: %v %v", sa, sb)
+	}
+
+	// cuts returns the new vertices of the front part
+	cuts := func(poly bsp.Polygon) map[[3]float64]bool {
+		_, f, _ := pl.Split(poly)
+		m := make(map[[3]float64]bool)
+		for _, v := range f.Vertices {
+			m[[3]float64{v[0], v[1], v[2]}] = true
+		}
+		for _, v := range poly.Vertices {
+			delete(m, [3]float64{v[0], v[1], v[2]})
+		}
+		return m
+	}
+	shared := 0
+	cp, cq := cuts(p), cuts(q)
+	for v := range cp {
+		if cq[v] {
+			shared++
+		}
+	}
+	if shared == 0 {
+		t.Errorf("no shared cut point: %v %v", cp, cq)
+	}
+}
+
+func Test_ClipPolygons(t *testing.T) {
+	tree := bsp.Build(box([3]float64{0, 0, 0}, [3]float64{1, 1, 1}))
+	square := []bsp.Polygon{bsp.NewPolygon(
+		[]float64{-1, -1, 0.5}, []float64{2, -1, 0.5}, []float64{2, 2, 0.5}, []float64{-1, 2, 0.5},
+	)}
+	if a := area(tree.ClipPolygons(square)); a != 8 {
+		t.Errorf("outside area want: 8; got: %g", a)
+	}
+	tree.Invert()
+	if a := area(tree.ClipPolygons(square)); a != 1 {
+		t.Errorf("inside area want: 1; got: %g", a)
+	}
+
+	var empty *bsp.Node
+	if got := empty.ClipPolygons(square); len(got) != 1 {
+		t.Errorf("empty tree want: 1 polygon; got: %v", got)
+	}
+}
+
+func Test_Union(t *testing.T) {
+	a := bsp.Build(box([3]float64{0, 0, 0}, [3]float64{1, 1, 1}))
+	b := bsp.Build(box([3]float64{0.5, 0, 0}, [3]float64{1.5, 1, 1}))
+	a.ClipTo(b)
+	b.ClipTo(a)
+	b.Invert()
+	b.ClipTo(a)
+	b.Invert()
+	a.Add(b.AllPolygons())
+
+	if got := area(a.AllPolygons()); math.Abs(got-8) > 1e-12 {
+		t.Errorf("union area want: 8; got: %g", got)
+	}
+}
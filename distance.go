@@ -0,0 +1,82 @@
+package robust
+
+// CompareDistance2 returns a positive value if q is farther from p than
+// r is; a negative value if q is closer to p than r is; and zero if they
+// are equidistant. The result is also a rough approximation of the
+// difference of the squared distances |p-q|² - |p-r|².
+//
+// Each slice parameter must contain at least 2 values.
+func CompareDistance2(p, q, r []float64) float64 {
+	return compareDistance2(p[0], p[1], q[0], q[1], r[0], r[1])
+}
+
+// CompareDistance2Vec is similiar to `CompareDistance2` but takes a
+// point-like struct pointer rather than a slice.
+func CompareDistance2Vec(p, q, r *XY) float64 {
+	return compareDistance2(p.X, p.Y, q.X, q.Y, r.X, r.Y)
+}
+
+// CompareDistance3 returns a positive value if q is farther from p than
+// r is; a negative value if q is closer to p than r is; and zero if they
+// are equidistant. The result is also a rough approximation of the
+// difference of the squared distances |p-q|² - |p-r|².
+//
+// Each slice parameter must contain at least 3 values.
+func CompareDistance3(p, q, r []float64) float64 {
+	return compareDistance3(p[0], p[1], p[2], q[0], q[1], q[2], r[0], r[1], r[2])
+}
+
+// CompareDistance3Vec is similiar to `CompareDistance3` but takes a
+// point-like struct pointer rather than a slice.
+func CompareDistance3Vec(p, q, r *XYZ) float64 {
+	return compareDistance3(p.X, p.Y, p.Z, q.X, q.Y, q.Z, r.X, r.Y, r.Z)
+}
+
+// compareDistance2 implements the basic error bound checks before
+// falling back to exact expansion arithmetic.
+func compareDistance2(px, py, qx, qy, rx, ry float64) float64 {
+	pqx, pqy := px-qx, py-qy
+	prx, pry := px-rx, py-ry
+
+	qlift := float64(pqx*pqx) + float64(pqy*pqy)
+	rlift := float64(prx*prx) + float64(pry*pry)
+	det := qlift - rlift
+
+	errbound := cd2errboundA * (qlift + rlift)
+	if (det > errbound) || (-det > errbound) {
+		return det
+	}
+
+	return compareDistanceExact([]float64{px, py}, []float64{qx, qy}, []float64{rx, ry})
+}
+
+// compareDistance3 implements the basic error bound checks before
+// falling back to exact expansion arithmetic.
+func compareDistance3(px, py, pz, qx, qy, qz, rx, ry, rz float64) float64 {
+	pqx, pqy, pqz := px-qx, py-qy, pz-qz
+	prx, pry, prz := px-rx, py-ry, pz-rz
+
+	qlift := float64(pqx*pqx) + float64(pqy*pqy) + float64(pqz*pqz)
+	rlift := float64(prx*prx) + float64(pry*pry) + float64(prz*prz)
+	det := qlift - rlift
+
+	errbound := cd3errboundA * (qlift + rlift)
+	if (det > errbound) || (-det > errbound) {
+		return det
+	}
+
+	return compareDistanceExact([]float64{px, py, pz}, []float64{qx, qy, qz}, []float64{rx, ry, rz})
+}
+
+// compareDistanceExact evaluates |p-q|² - |p-r|² exactly and returns the
+// largest component of the resulting expansion.
+func compareDistanceExact(p, q, r []float64) float64 {
+	det := []float64{0}
+	for i := range p {
+		pq := diffExpansion(p[i], q[i])
+		pr := diffExpansion(p[i], r[i])
+		det = sumExpansion(det, mulExpansion(pq, pq))
+		det = subExpansion(det, mulExpansion(pr, pr))
+	}
+	return det[len(det)-1]
+}
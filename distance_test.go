@@ -0,0 +1,87 @@
+package robust_test
+
+import (
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+func Test_CompareDistance2(t *testing.T) {
+	fixtures := loadCases(t, "distance2.txt", 6)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			p := []float64{tt.args[0], tt.args[1]}
+			q := []float64{tt.args[2], tt.args[3]}
+			r := []float64{tt.args[4], tt.args[5]}
+			res := robust.CompareDistance2(p, q, r)
+			assert(t, tt.sign, res)
+
+			vp := Vec2{tt.args[0], tt.args[1]}
+			vq := Vec2{tt.args[2], tt.args[3]}
+			vr := Vec2{tt.args[4], tt.args[5]}
+			res = robust.CompareDistance2Vec((*robust.XY)(&vp), (*robust.XY)(&vq), (*robust.XY)(&vr))
+			assert(t, tt.sign, res)
+		})
+	}
+}
+
+func Test_CompareDistance3(t *testing.T) {
+	fixtures := loadCases(t, "distance3.txt", 9)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			p := []float64{tt.args[0], tt.args[1], tt.args[2]}
+			q := []float64{tt.args[3], tt.args[4], tt.args[5]}
+			r := []float64{tt.args[6], tt.args[7], tt.args[8]}
+			res := robust.CompareDistance3(p, q, r)
+			assert(t, tt.sign, res)
+
+			vp := Vec3{tt.args[0], tt.args[1], tt.args[2]}
+			vq := Vec3{tt.args[3], tt.args[4], tt.args[5]}
+			vr := Vec3{tt.args[6], tt.args[7], tt.args[8]}
+			res = robust.CompareDistance3Vec((*robust.XYZ)(&vp), (*robust.XYZ)(&vq), (*robust.XYZ)(&vr))
+			assert(t, tt.sign, res)
+		})
+	}
+}
+
+func Benchmark_CompareDistance2(b *testing.B) {
+	fixtures := loadCases(b, "distance2.txt", 6)
+	tests := make([][3][]float64, len(fixtures))
+	for i, tt := range fixtures {
+		tests[i] = [3][]float64{
+			{tt.args[0], tt.args[1]},
+			{tt.args[2], tt.args[3]},
+			{tt.args[4], tt.args[5]},
+		}
+	}
+
+	b.ResetTimer()
+	var res float64
+	for n := 0; n < b.N; n++ {
+		for _, arr := range tests {
+			res = robust.CompareDistance2(arr[0], arr[1], arr[2])
+		}
+	}
+	result = res
+}
+
+func Benchmark_CompareDistance3(b *testing.B) {
+	fixtures := loadCases(b, "distance3.txt", 9)
+	tests := make([][3][]float64, len(fixtures))
+	for i, tt := range fixtures {
+		tests[i] = [3][]float64{
+			{tt.args[0], tt.args[1], tt.args[2]},
+			{tt.args[3], tt.args[4], tt.args[5]},
+			{tt.args[6], tt.args[7], tt.args[8]},
+		}
+	}
+
+	b.ResetTimer()
+	var res float64
+	for n := 0; n < b.N; n++ {
+		for _, arr := range tests {
+			res = robust.CompareDistance3(arr[0], arr[1], arr[2])
+		}
+	}
+	result = res
+}
@@ -0,0 +1,84 @@
+package robust
+
+import "math"
+
+// DotSign2 returns a positive value if the angle at b in the triangle
+// abc is acute; a negative value if it's obtuse; and zero if it's a
+// right angle. The result is also a rough approximation of the dot
+// product (a-b)·(c-b).
+//
+// Equivalently, a negative value means b lies strictly inside the
+// diametral circle of the segment ac, which is the encroachment test
+// used by Delaunay refinement.
+//
+// Each slice parameter must contain at least 2 values.
+func DotSign2(a, b, c []float64) float64 {
+	return dotSign2(a[0], a[1], b[0], b[1], c[0], c[1])
+}
+
+// DotSign2Vec is similiar to `DotSign2` but takes a point-like struct
+// pointer rather than a slice.
+func DotSign2Vec(a, b, c *XY) float64 {
+	return dotSign2(a.X, a.Y, b.X, b.Y, c.X, c.Y)
+}
+
+// DotSign3 returns a positive value if the angle at b in the triangle
+// abc is acute; a negative value if it's obtuse; and zero if it's a
+// right angle. The result is also a rough approximation of the dot
+// product (a-b)·(c-b).
+//
+// Equivalently, a negative value means b lies strictly inside the
+// diametral sphere of the segment ac.
+//
+// Each slice parameter must contain at least 3 values.
+func DotSign3(a, b, c []float64) float64 {
+	return dotSign3(a[0], a[1], a[2], b[0], b[1], b[2], c[0], c[1], c[2])
+}
+
+// DotSign3Vec is similiar to `DotSign3` but takes a point-like struct
+// pointer rather than a slice.
+func DotSign3Vec(a, b, c *XYZ) float64 {
+	return dotSign3(a.X, a.Y, a.Z, b.X, b.Y, b.Z, c.X, c.Y, c.Z)
+}
+
+// dotSign2 implements the basic error bound checks before falling back
+// to exact expansion arithmetic.
+func dotSign2(ax, ay, bx, by, cx, cy float64) float64 {
+	xx := float64((ax - bx) * (cx - bx))
+	yy := float64((ay - by) * (cy - by))
+	det := xx + yy
+
+	errbound := dot2errboundA * (math.Abs(xx) + math.Abs(yy))
+	if (det > errbound) || (-det > errbound) {
+		return det
+	}
+
+	return dotExact([]float64{ax, ay}, []float64{bx, by}, []float64{cx, cy})
+}
+
+// dotSign3 implements the basic error bound checks before falling back
+// to exact expansion arithmetic.
+func dotSign3(ax, ay, az, bx, by, bz, cx, cy, cz float64) float64 {
+	xx := float64((ax - bx) * (cx - bx))
+	yy := float64((ay - by) * (cy - by))
+	zz := float64((az - bz) * (cz - bz))
+	det := xx + yy + zz
+
+	errbound := dot3errboundA * (math.Abs(xx) + math.Abs(yy) + math.Abs(zz))
+	if (det > errbound) || (-det > errbound) {
+		return det
+	}
+
+	return dotExact([]float64{ax, ay, az}, []float64{bx, by, bz}, []float64{cx, cy, cz})
+}
+
+// dotExact evaluates (a-b)·(c-b) exactly and returns the largest
+// component of the resulting expansion.
+func dotExact(a, b, c []float64) float64 {
+	det := []float64{0}
+	for i := range a {
+		term := mulExpansion(diffExpansion(a[i], b[i]), diffExpansion(c[i], b[i]))
+		det = sumExpansion(det, term)
+	}
+	return det[len(det)-1]
+}
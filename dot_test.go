@@ -0,0 +1,87 @@
+package robust_test
+
+import (
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+func Test_DotSign2(t *testing.T) {
+	fixtures := loadCases(t, "dot2.txt", 6)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			a := []float64{tt.args[0], tt.args[1]}
+			b := []float64{tt.args[2], tt.args[3]}
+			c := []float64{tt.args[4], tt.args[5]}
+			res := robust.DotSign2(a, b, c)
+			assert(t, tt.sign, res)
+
+			va := Vec2{tt.args[0], tt.args[1]}
+			vb := Vec2{tt.args[2], tt.args[3]}
+			vc := Vec2{tt.args[4], tt.args[5]}
+			res = robust.DotSign2Vec((*robust.XY)(&va), (*robust.XY)(&vb), (*robust.XY)(&vc))
+			assert(t, tt.sign, res)
+		})
+	}
+}
+
+func Test_DotSign3(t *testing.T) {
+	fixtures := loadCases(t, "dot3.txt", 9)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			a := []float64{tt.args[0], tt.args[1], tt.args[2]}
+			b := []float64{tt.args[3], tt.args[4], tt.args[5]}
+			c := []float64{tt.args[6], tt.args[7], tt.args[8]}
+			res := robust.DotSign3(a, b, c)
+			assert(t, tt.sign, res)
+
+			va := Vec3{tt.args[0], tt.args[1], tt.args[2]}
+			vb := Vec3{tt.args[3], tt.args[4], tt.args[5]}
+			vc := Vec3{tt.args[6], tt.args[7], tt.args[8]}
+			res = robust.DotSign3Vec((*robust.XYZ)(&va), (*robust.XYZ)(&vb), (*robust.XYZ)(&vc))
+			assert(t, tt.sign, res)
+		})
+	}
+}
+
+func Benchmark_DotSign2(b *testing.B) {
+	fixtures := loadCases(b, "dot2.txt", 6)
+	tests := make([][3][]float64, len(fixtures))
+	for i, tt := range fixtures {
+		tests[i] = [3][]float64{
+			{tt.args[0], tt.args[1]},
+			{tt.args[2], tt.args[3]},
+			{tt.args[4], tt.args[5]},
+		}
+	}
+
+	b.ResetTimer()
+	var res float64
+	for n := 0; n < b.N; n++ {
+		for _, arr := range tests {
+			res = robust.DotSign2(arr[0], arr[1], arr[2])
+		}
+	}
+	result = res
+}
+
+func Benchmark_DotSign3(b *testing.B) {
+	fixtures := loadCases(b, "dot3.txt", 9)
+	tests := make([][3][]float64, len(fixtures))
+	for i, tt := range fixtures {
+		tests[i] = [3][]float64{
+			{tt.args[0], tt.args[1], tt.args[2]},
+			{tt.args[3], tt.args[4], tt.args[5]},
+			{tt.args[6], tt.args[7], tt.args[8]},
+		}
+	}
+
+	b.ResetTimer()
+	var res float64
+	for n := 0; n < b.N; n++ {
+		for _, arr := range tests {
+			res = robust.DotSign3(arr[0], arr[1], arr[2])
+		}
+	}
+	result = res
+}
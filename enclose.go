@@ -0,0 +1,254 @@
+package robust
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Circle is a minimal enclosing circle. The circle is exactly defined
+// by its support points, while the center and radius are floating-point
+// approximations computed from them.
+type Circle struct {
+	Center [2]float64
+	Radius float64
+	// Support holds the indices of the 1 to 3 points on the boundary
+	// that define the circle.
+	Support []int
+}
+
+// Sphere is a minimal enclosing sphere. The sphere is exactly defined
+// by its support points, while the center and radius are floating-point
+// approximations computed from them.
+type Sphere struct {
+	Center [3]float64
+	Radius float64
+	// Support holds the indices of the 1 to 4 points on the boundary
+	// that define the sphere.
+	Support []int
+}
+
+// MinEnclosingCircle returns the smallest circle containing all the
+// points in the flat buffer of XY pairs, using Welzl's randomized
+// incremental algorithm. Every containment decision is made with exact
+// predicates, either an in-diametral-circle test or `InCircle`, so the
+// support set is always correct even for cocircular and duplicate points.
+//
+// The zero Circle is returned for an empty buffer.
+func MinEnclosingCircle(points []float64) Circle {
+	n := len(points) / 2
+	if n == 0 {
+		return Circle{}
+	}
+	pt := func(i int) []float64 { return points[2*i : 2*i+2] }
+	order := rand.New(rand.NewSource(1)).Perm(n)
+
+	support := []int{order[0]}
+	for i := 1; i < n; i++ {
+		pi := order[i]
+		if inCircleSupport(points, support, pt(pi)) {
+			continue
+		}
+		support = []int{pi}
+		for j := 0; j < i; j++ {
+			pj := order[j]
+			if inCircleSupport(points, support, pt(pj)) {
+				continue
+			}
+			support = []int{pi, pj}
+			for k := 0; k < j; k++ {
+				pk := order[k]
+				if inCircleSupport(points, support, pt(pk)) {
+					continue
+				}
+				support = []int{pi, pj, pk}
+			}
+		}
+	}
+
+	c := Circle{Support: support}
+	switch len(support) {
+	case 1:
+		copy(c.Center[:], pt(support[0]))
+	case 2:
+		a, b := pt(support[0]), pt(support[1])
+		c.Center = [2]float64{a[0] + (b[0]-a[0])/2, a[1] + (b[1]-a[1])/2}
+	case 3:
+		a, b, d := pt(support[0]), pt(support[1]), pt(support[2])
+		bx, by := b[0]-a[0], b[1]-a[1]
+		dx, dy := d[0]-a[0], d[1]-a[1]
+		blift, dlift := bx*bx+by*by, dx*dx+dy*dy
+		denom := 2 * (bx*dy - by*dx)
+		c.Center = [2]float64{a[0] + (dy*blift-by*dlift)/denom, a[1] + (bx*dlift-dx*blift)/denom}
+	}
+	for _, s := range support {
+		p := pt(s)
+		c.Radius = math.Max(c.Radius, math.Hypot(p[0]-c.Center[0], p[1]-c.Center[1]))
+	}
+	return c
+}
+
+// MinEnclosingSphere is the 3D equivalent of `MinEnclosingCircle` for
+// a flat buffer of XYZ triples, using an in-diametral-ball test for two
+// support points, an exact in-circumball test for three and `InSphere`
+// for four.
+//
+// The zero Sphere is returned for an empty buffer.
+func MinEnclosingSphere(points []float64) Sphere {
+	n := len(points) / 3
+	if n == 0 {
+		return Sphere{}
+	}
+	pt := func(i int) []float64 { return points[3*i : 3*i+3] }
+	order := rand.New(rand.NewSource(1)).Perm(n)
+
+	support := []int{order[0]}
+	for i := 1; i < n; i++ {
+		pi := order[i]
+		if inSphereSupport(points, support, pt(pi)) {
+			continue
+		}
+		support = []int{pi}
+		for j := 0; j < i; j++ {
+			pj := order[j]
+			if inSphereSupport(points, support, pt(pj)) {
+				continue
+			}
+			support = []int{pi, pj}
+			for k := 0; k < j; k++ {
+				pk := order[k]
+				if inSphereSupport(points, support, pt(pk)) {
+					continue
+				}
+				support = []int{pi, pj, pk}
+				for l := 0; l < k; l++ {
+					pl := order[l]
+					if inSphereSupport(points, support, pt(pl)) {
+						continue
+					}
+					support = []int{pi, pj, pk, pl}
+				}
+			}
+		}
+	}
+
+	s := Sphere{Support: support}
+	switch len(support) {
+	case 1:
+		copy(s.Center[:], pt(support[0]))
+	case 2:
+		a, b := pt(support[0]), pt(support[1])
+		for i := range s.Center {
+			s.Center[i] = a[i] + (b[i]-a[i])/2
+		}
+	case 3:
+		a := pt(support[0])
+		u, v := sub3(pt(support[1]), a), sub3(pt(support[2]), a)
+		uv := cross3(u, v)
+		num := cross3(sub3(scale3(v, dot3(u, u)), scale3(u, dot3(v, v))), uv)
+		denom := 2 * dot3(uv, uv)
+		for i := range s.Center {
+			s.Center[i] = a[i] + num[i]/denom
+		}
+	case 4:
+		d := pt(support[3])
+		a, b, c := sub3(pt(support[0]), d), sub3(pt(support[1]), d), sub3(pt(support[2]), d)
+		bc, ca, ab := cross3(b, c), cross3(c, a), cross3(a, b)
+		denom := 2 * dot3(a, bc)
+		for i := range s.Center {
+			s.Center[i] = d[i] + (dot3(a, a)*bc[i]+dot3(b, b)*ca[i]+dot3(c, c)*ab[i])/denom
+		}
+	}
+	for _, i := range support {
+		p := pt(i)
+		r := math.Sqrt(dot3(sub3(p, s.Center[:]), sub3(p, s.Center[:])))
+		s.Radius = math.Max(s.Radius, r)
+	}
+	return s
+}
+
+// inCircleSupport reports whether p is inside or on the circle defined
+// by the support indices into the 2D points buffer.
+func inCircleSupport(points []float64, support []int, p []float64) bool {
+	pt := func(i int) []float64 { return points[2*i : 2*i+2] }
+	switch len(support) {
+	case 1:
+		a := pt(support[0])
+		return a[0] == p[0] && a[1] == p[1]
+	case 2:
+		return DotSign2(pt(support[0]), p, pt(support[1])) <= 0
+	default:
+		a, b, c := pt(support[0]), pt(support[1]), pt(support[2])
+		det := InCircle(a, b, c, p)
+		if Orient2(a, b, c) < 0 {
+			det = -det
+		}
+		return det >= 0
+	}
+}
+
+// inSphereSupport reports whether p is inside or on the sphere defined
+// by the support indices into the 3D points buffer.
+func inSphereSupport(points []float64, support []int, p []float64) bool {
+	pt := func(i int) []float64 { return points[3*i : 3*i+3] }
+	switch len(support) {
+	case 1:
+		a := pt(support[0])
+		return a[0] == p[0] && a[1] == p[1] && a[2] == p[2]
+	case 2:
+		return DotSign3(pt(support[0]), p, pt(support[1])) <= 0
+	case 3:
+		return inCircumball3(pt(support[0]), pt(support[1]), pt(support[2]), p) >= 0
+	default:
+		a, b, c, d := pt(support[0]), pt(support[1]), pt(support[2]), pt(support[3])
+		det := InSphere(a, b, c, d, p)
+		if Orient3(a, b, c, d) < 0 {
+			det = -det
+		}
+		return det >= 0
+	}
+}
+
+// inCircumball3 returns the exact sign of whether p is inside (positive),
+// outside (negative) or on (zero) the smallest sphere through the 3D
+// points a, b, and c. That sphere is centered at a + N/D in the plane of
+// the triangle where, with u = b-a, v = c-a and n = u×v,
+//
+//	N = (|u|²v - |v|²u) × n    and    D = 2|n|²
+//
+// so for w = p-a the test reduces to the sign of w·N - |n|²|w|².
+// The result is zero for collinear a, b, and c.
+func inCircumball3(a, b, c, p []float64) int {
+	u, v, w := diffVector(b, a), diffVector(c, a), diffVector(p, a)
+	n := crossExpansion(u, v)
+	nn := dotExpansion(n, n)
+	if signExpansion(nn) == 0 {
+		return 0
+	}
+	uu, vv := dotExpansion(u, u), dotExpansion(v, v)
+	var m [3][]float64
+	for i := 0; i < 3; i++ {
+		m[i] = subExpansion(mulExpansion(uu, v[i]), mulExpansion(vv, u[i]))
+	}
+	wn := dotExpansion(w, crossExpansion(m, n))
+	return signExpansion(subExpansion(wn, mulExpansion(nn, dotExpansion(w, w))))
+}
+
+func sub3(a, b []float64) []float64 {
+	return []float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func scale3(a []float64, s float64) []float64 {
+	return []float64{a[0] * s, a[1] * s, a[2] * s}
+}
+
+func dot3(a, b []float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross3(a, b []float64) []float64 {
+	return []float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
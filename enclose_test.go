@@ -0,0 +1,81 @@
+package robust_test
+
+import (
+	"math"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+func Test_MinEnclosingCircle(t *testing.T) {
+	tests := []struct {
+		label  string
+		points []float64
+		radius float64
+	}{
+		{"single", []float64{1, 2}, 0},
+		{"duplicates", []float64{1, 2, 1, 2, 1, 2}, 0},
+		{"collinear", []float64{0, 0, 1, 1, 2, 2, 3, 3, -1, -1}, 2 * math.Sqrt2},
+		{"square", []float64{0, 0, 2, 0, 2, 2, 0, 2, 1, 1, 0.5, 1.5}, math.Sqrt2},
+		{"cocircular", []float64{
+			3, 4, -3, 4, 3, -4, -3, -4, 4, 3, -4, 3, 4, -3, -4, -3,
+			5, 0, -5, 0, 0, 5, 0, -5, 1, 1, -2, 2,
+		}, 5},
+		{"triangle", []float64{0, 0, 4, 0, 2, 3, 2, 1}, 13.0 / 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			c := robust.MinEnclosingCircle(tt.points)
+			if math.Abs(c.Radius-tt.radius) > 1e-12 {
+				t.Errorf("radius want: %g; got: %g", tt.radius, c.Radius)
+			}
+			for i := 0; i < len(tt.points); i += 2 {
+				d := math.Hypot(tt.points[i]-c.Center[0], tt.points[i+1]-c.Center[1])
+				if d > c.Radius*(1+1e-12) {
+					t.Errorf("point %d outside: %g > %g", i/2, d, c.Radius)
+				}
+			}
+		})
+	}
+
+	if c := robust.MinEnclosingCircle(nil); c.Support != nil {
+		t.Errorf("empty: got: %+v", c)
+	}
+}
+
+func Test_MinEnclosingSphere(t *testing.T) {
+	tests := []struct {
+		label  string
+		points []float64
+		radius float64
+	}{
+		{"single", []float64{1, 2, 3}, 0},
+		{"collinear", []float64{0, 0, 0, 1, 1, 1, 2, 2, 2, -1, -1, -1}, 1.5 * math.Sqrt(3)},
+		{"coplanar", []float64{0, 0, 5, 2, 0, 5, 2, 2, 5, 0, 2, 5, 1, 1, 5}, math.Sqrt2},
+		{"cube", []float64{
+			0, 0, 0, 1, 0, 0, 0, 1, 0, 1, 1, 0,
+			0, 0, 1, 1, 0, 1, 0, 1, 1, 1, 1, 1, 0.5, 0.5, 0.5,
+		}, math.Sqrt(3) / 2},
+		{"cospherical", []float64{
+			3, 0, 0, -3, 0, 0, 0, 3, 0, 0, -3, 0, 0, 0, 3, 0, 0, -3,
+			1, 2, 2, -1, 2, 2, 1, -2, 2, 1, 2, -2, 2, 1, -2, -2, -2, 1,
+			0, 1, 0, 1, 1, 1,
+		}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			s := robust.MinEnclosingSphere(tt.points)
+			if math.Abs(s.Radius-tt.radius) > 1e-12 {
+				t.Errorf("radius want: %g; got: %g", tt.radius, s.Radius)
+			}
+			for i := 0; i < len(tt.points); i += 3 {
+				dx := tt.points[i] - s.Center[0]
+				dy := tt.points[i+1] - s.Center[1]
+				dz := tt.points[i+2] - s.Center[2]
+				if d := math.Sqrt(dx*dx + dy*dy + dz*dz); d > s.Radius*(1+1e-12) {
+					t.Errorf("point %d outside: %g > %g", i/3, d, s.Radius)
+				}
+			}
+		})
+	}
+}
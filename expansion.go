@@ -0,0 +1,401 @@
+package robust
+
+import "math"
+
+// This file is a go port of the expansion arithmetic from `predicates.c`
+// used by the predicates that don't exist in the C library. Expansions
+// are slices sorted by increasing magnitude with zero components removed,
+// except that zero itself is represented by the single component 0.
+//
+// The explicit float64 conversions mirror the `(REAL)` casts in the C
+// macros. Go allows fusing `x*y + z` into an FMA unless an intermediate
+// result is converted, which would otherwise break the error-free
+// transformations below.
+//
+// Exact products use a fused multiply-add for the rounding error when the
+// CPU has one and Dekker's splitting otherwise, like `Two_Product`.
+// Splitting scales operands and products too large for `splitter*a` or
+// the partial products to stay finite, so both compute the same two
+// components for every product whose rounding error doesn't underflow,
+// and expansions are the same either way. Overflowing products give an
+// infinite x and y == -x on both. Only rounding errors below about
+// 2^-1022 lose bits, differently on each.
+
+// fastTwoSum computes x + y == a + b exactly, assuming |a| >= |b|.
+func fastTwoSum(a, b float64) (x, y float64) {
+	x = float64(a + b)
+	bvirt := x - a
+	y = b - bvirt
+	return x, y
+}
+
+// twoSum computes x + y == a + b exactly.
+func twoSum(a, b float64) (x, y float64) {
+	x = float64(a + b)
+	bvirt := float64(x - a)
+	avirt := x - bvirt
+	bround := b - bvirt
+	around := a - avirt
+	y = around + bround
+	return x, y
+}
+
+// twoDiff computes x + y == a - b exactly.
+func twoDiff(a, b float64) (x, y float64) {
+	x = float64(a - b)
+	bvirt := float64(a - x)
+	avirt := x + bvirt
+	bround := bvirt - b
+	around := a - avirt
+	y = around + bround
+	return x, y
+}
+
+// splitScale rescales the larger operand of products too large to split.
+const splitScale = 1 << 64
+
+// split divides a into two non-overlapping halves of 26 bits each. The
+// halves are NaN when splitter*a overflows, above about 2^996.
+func split(a float64) (hi, lo float64) {
+	c := float64(splitter * a)
+	abig := float64(c - a)
+	hi = c - abig
+	lo = a - hi
+	return hi, lo
+}
+
+// twoProduct computes x + y == a * b exactly.
+func twoProduct(a, b float64) (x, y float64) {
+	if fmaProducts {
+		return twoProductFMA(a, b)
+	}
+	return twoProductSplit(a, b)
+}
+
+// twoProductSplit is twoProduct with Dekker's splitting.
+func twoProductSplit(a, b float64) (x, y float64) {
+	x = float64(a * b)
+	ahi, alo := split(a)
+	bhi, blo := split(b)
+	err1 := x - float64(ahi*bhi)
+	err2 := err1 - float64(alo*bhi)
+	err3 := err2 - float64(ahi*blo)
+	y = float64(alo*blo) - err3
+	if y-y != 0 {
+		return twoProductLarge(a, b, x)
+	}
+	return x, y
+}
+
+// twoProductLarge redoes the products whose error isn't finite after
+// splitting. Either an operand was too large to split or a partial
+// product overflowed, and the product is computed with the larger
+// operand scaled down by 2^64, which keeps the rounding error from
+// underflowing. When the product itself overflows its error is -x, or
+// NaN for infinite or NaN operands, as a fused multiply-add gives.
+func twoProductLarge(a, b, x float64) (float64, float64) {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) || math.IsNaN(x) {
+		return x, math.NaN()
+	}
+	if math.IsInf(x, 0) {
+		return x, -x
+	}
+	if math.Abs(a) < math.Abs(b) {
+		a, b = b, a
+	}
+	x, y := twoProductSplit(a/splitScale, b)
+	return x * splitScale, y * splitScale
+}
+
+// diffExpansion returns the exact expansion of a - b.
+func diffExpansion(a, b float64) []float64 {
+	x, y := twoDiff(a, b)
+	if y == 0 {
+		return []float64{x}
+	}
+	return []float64{y, x}
+}
+
+// productExpansion returns the exact expansion of a * b.
+func productExpansion(a, b float64) []float64 {
+	x, y := twoProduct(a, b)
+	if y == 0 {
+		return []float64{x}
+	}
+	return []float64{y, x}
+}
+
+// growExpansion is the port of `grow_expansion_zeroelim`, returning e + b.
+func growExpansion(e []float64, b float64) []float64 {
+	h := make([]float64, 0, len(e)+1)
+	q := b
+	for _, enow := range e {
+		var hh float64
+		q, hh = twoSum(q, enow)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return h
+}
+
+// sumExpansion is the port of `fast_expansion_sum_zeroelim`, returning
+// e + f as a new expansion.
+func sumExpansion(e, f []float64) []float64 {
+	h := make([]float64, 0, len(e)+len(f))
+	ei, fi := 0, 0
+	enow, fnow := e[0], f[0]
+
+	var q, hh float64
+	if (fnow > enow) == (fnow > -enow) {
+		q = enow
+		ei++
+	} else {
+		q = fnow
+		fi++
+	}
+	if ei < len(e) && fi < len(f) {
+		enow, fnow = e[ei], f[fi]
+		if (fnow > enow) == (fnow > -enow) {
+			q, hh = fastTwoSum(enow, q)
+			ei++
+		} else {
+			q, hh = fastTwoSum(fnow, q)
+			fi++
+		}
+		if hh != 0 {
+			h = append(h, hh)
+		}
+		for ei < len(e) && fi < len(f) {
+			enow, fnow = e[ei], f[fi]
+			if (fnow > enow) == (fnow > -enow) {
+				q, hh = twoSum(q, enow)
+				ei++
+			} else {
+				q, hh = twoSum(q, fnow)
+				fi++
+			}
+			if hh != 0 {
+				h = append(h, hh)
+			}
+		}
+	}
+	for ; ei < len(e); ei++ {
+		q, hh = twoSum(q, e[ei])
+		if hh != 0 {
+			h = append(h, hh)
+		}
+	}
+	for ; fi < len(f); fi++ {
+		q, hh = twoSum(q, f[fi])
+		if hh != 0 {
+			h = append(h, hh)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return h
+}
+
+// subExpansion returns e - f as a new expansion.
+func subExpansion(e, f []float64) []float64 {
+	return sumExpansion(e, negExpansion(f))
+}
+
+// negExpansion returns -e as a new expansion.
+func negExpansion(e []float64) []float64 {
+	h := make([]float64, len(e))
+	for i, v := range e {
+		h[i] = -v
+	}
+	return h
+}
+
+// scaleExpansion is the port of `scale_expansion_zeroelim`, returning
+// e * b as a new expansion.
+func scaleExpansion(e []float64, b float64) []float64 {
+	h := make([]float64, 0, 2*len(e))
+	var bhi, blo float64
+	if !fmaProducts {
+		bhi, blo = split(b)
+	}
+	q, hh := twoProductPresplit(e[0], b, bhi, blo)
+	if hh != 0 {
+		h = append(h, hh)
+	}
+	for _, enow := range e[1:] {
+		product1, product0 := twoProductPresplit(enow, b, bhi, blo)
+		var sum float64
+		sum, hh = twoSum(q, product0)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+		q, hh = fastTwoSum(product1, sum)
+		if hh != 0 {
+			h = append(h, hh)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return h
+}
+
+// twoProductPresplit is twoProduct with b already split, unless the
+// products use FMA.
+func twoProductPresplit(a, b, bhi, blo float64) (x, y float64) {
+	if fmaProducts {
+		return twoProductFMA(a, b)
+	}
+	x = float64(a * b)
+	ahi, alo := split(a)
+	err1 := x - float64(ahi*bhi)
+	err2 := err1 - float64(alo*bhi)
+	err3 := err2 - float64(ahi*blo)
+	y = float64(alo*blo) - err3
+	if y-y != 0 {
+		return twoProductLarge(a, b, x)
+	}
+	return x, y
+}
+
+// mulExpansion returns e * f as a new expansion by summing the scaled
+// expansions of each component of the shorter operand.
+func mulExpansion(e, f []float64) []float64 {
+	if len(e) < len(f) {
+		e, f = f, e
+	}
+	h := scaleExpansion(e, f[0])
+	for _, b := range f[1:] {
+		h = sumExpansion(h, scaleExpansion(e, b))
+	}
+	return h
+}
+
+// estimate is the port of `estimate`, a rough float64 approximation of
+// the value of the expansion.
+func estimate(e []float64) float64 {
+	q := e[0]
+	for _, v := range e[1:] {
+		q += v
+	}
+	return q
+}
+
+// compress is the port of `compress`, returning an expansion of the same
+// value whose largest component approximates it with an error smaller
+// than one ulp of that component.
+func compress(e []float64) []float64 {
+	h := make([]float64, len(e))
+	q := e[len(e)-1]
+	bottom := len(e) - 1
+	for i := len(e) - 2; i >= 0; i-- {
+		qnew, small := fastTwoSum(q, e[i])
+		if small != 0 {
+			h[bottom] = qnew
+			bottom--
+			q = small
+		} else {
+			q = qnew
+		}
+	}
+	top := 0
+	for i := bottom + 1; i < len(e); i++ {
+		qnew, small := fastTwoSum(h[i], q)
+		if small != 0 {
+			h[top] = small
+			top++
+		}
+		q = qnew
+	}
+	h[top] = q
+	return h[:top+1]
+}
+
+// approximate returns the largest component of the compressed e, which
+// is within 2ε relative error of its value.
+func approximate(e []float64) float64 {
+	c := compress(e)
+	return c[len(c)-1]
+}
+
+// roundExpansion returns the value of e correctly rounded to the nearest
+// float64, with ties to even. It's the final step of Python's `math.fsum`,
+// which sums the components from the top until one is lost to rounding.
+// The rounding error is then at most half an ulp and only matters when
+// it's exactly half, in which case the next component breaks the tie.
+// Zero is always returned as +0.
+func roundExpansion(e []float64) float64 {
+	n := len(e) - 1
+	hi, lo := e[n], 0.0
+	for n > 0 {
+		n--
+		hi, lo = fastTwoSum(hi, e[n])
+		if lo != 0 {
+			break
+		}
+	}
+	if n > 0 && (lo < 0 && e[n-1] < 0 || lo > 0 && e[n-1] > 0) {
+		y := lo * 2
+		x := float64(hi + y)
+		if y == float64(x-hi) {
+			hi = x
+		}
+	}
+	if hi == 0 {
+		return 0
+	}
+	return hi
+}
+
+// signExpansion returns the exact sign of e, which is the sign of its
+// largest magnitude component.
+func signExpansion(e []float64) int {
+	top := e[len(e)-1]
+	if top > 0 {
+		return 1
+	}
+	if top < 0 {
+		return -1
+	}
+	return 0
+}
+
+// crossExpansion is the exact cross product of two expansion 3-vectors.
+func crossExpansion(a, b [3][]float64) [3][]float64 {
+	return [3][]float64{
+		subExpansion(mulExpansion(a[1], b[2]), mulExpansion(a[2], b[1])),
+		subExpansion(mulExpansion(a[2], b[0]), mulExpansion(a[0], b[2])),
+		subExpansion(mulExpansion(a[0], b[1]), mulExpansion(a[1], b[0])),
+	}
+}
+
+// dotExpansion is the exact dot product of two expansion 3-vectors.
+func dotExpansion(a, b [3][]float64) []float64 {
+	sum := mulExpansion(a[0], b[0])
+	sum = sumExpansion(sum, mulExpansion(a[1], b[1]))
+	return sumExpansion(sum, mulExpansion(a[2], b[2]))
+}
+
+// diffVector returns the exact expansion 3-vector of a - b.
+func diffVector(a, b []float64) [3][]float64 {
+	return [3][]float64{
+		diffExpansion(a[0], b[0]),
+		diffExpansion(a[1], b[1]),
+		diffExpansion(a[2], b[2]),
+	}
+}
+
+// scaleVector returns the exact expansion 3-vector of s * v.
+func scaleVector(v [3][]float64, s []float64) [3][]float64 {
+	return [3][]float64{mulExpansion(v[0], s), mulExpansion(v[1], s), mulExpansion(v[2], s)}
+}
+
+// sumVector returns the exact expansion 3-vector of a + b.
+func sumVector(a, b [3][]float64) [3][]float64 {
+	return [3][]float64{sumExpansion(a[0], b[0]), sumExpansion(a[1], b[1]), sumExpansion(a[2], b[2])}
+}
@@ -0,0 +1,51 @@
+package robust
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func Test_RoundExpansion(t *testing.T) {
+	// half is half an ulp of 1 and tiny is far below it.
+	half, tiny := math.Ldexp(1, -53), math.Ldexp(1, -100)
+	tests := []struct {
+		e    []float64
+		want float64
+	}{
+		{[]float64{0}, 0},
+		{[]float64{-3}, -3},
+		// Exact ties round to even, otherwise the lower components
+		// decide the direction.
+		{[]float64{half, 1}, 1},
+		{[]float64{half, 1 + 2*half}, 1 + 4*half},
+		{[]float64{tiny, half, 1}, 1 + 2*half},
+		{[]float64{-tiny, half, 1}, 1},
+		{[]float64{-tiny, -half, -1}, -1 - 2*half},
+		{[]float64{tiny, -half / 2, 1}, 1},
+		{[]float64{-tiny, -half / 2, 1}, 1 - half},
+	}
+	for _, tt := range tests {
+		if got := roundExpansion(tt.e); got != tt.want {
+			t.Errorf("%v: want %v; got %v", tt.e, tt.want, got)
+		}
+	}
+
+	// Random expansions of values with exponents far apart, so that the
+	// components straddle the rounding position.
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		e := []float64{0}
+		sum := new(big.Rat)
+		for j := rng.Intn(6); j >= 0; j-- {
+			x := math.Ldexp(float64(rng.Intn(1<<8)-1<<7), rng.Intn(160)-80)
+			e = growExpansion(e, x)
+			sum.Add(sum, new(big.Rat).SetFloat64(x))
+		}
+		want, _ := sum.Float64()
+		if got := roundExpansion(e); got != want {
+			t.Fatalf("%v: want %v; got %v", e, want, got)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !go1.14
+// +build !go1.14
+
+package robust
+
+// UseFMAProducts has no effect before Go 1.14, where the exact products
+// always split.
+func UseFMAProducts(fma bool) (restore func()) {
+	return func() {}
+}
@@ -0,0 +1,14 @@
+//go:build go1.14
+// +build go1.14
+
+package robust
+
+// UseFMAProducts switches the exact products to FMA or to splitting until
+// restore is called.
+func UseFMAProducts(fma bool) (restore func()) {
+	saved := fmaProducts
+	fmaProducts = fma
+	return func() {
+		fmaProducts = saved
+	}
+}
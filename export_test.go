@@ -0,0 +1,16 @@
+package robust
+
+// UseGenericBatch switches the batch predicates to the generic filters
+// until restore is called.
+func UseGenericBatch() (restore func()) {
+	orient2, orient3 := orient2BatchA, orient3BatchA
+	orient2BatchA, orient3BatchA = orient2BatchGeneric, orient3BatchGeneric
+	return func() {
+		orient2BatchA, orient3BatchA = orient2, orient3
+	}
+}
+
+// FusedFilters is set when the stage A filters fuse their products, so
+// the determinants they certify are rounded differently than the batch
+// filters.
+const FusedFilters = fusedFilters
@@ -0,0 +1,187 @@
+//go:build !fusedfilters
+// +build !fusedfilters
+
+package robust
+
+import "math"
+
+// The stage A filters of the basic predicates. Every product feeding a sum
+// is converted to float64, which the spec guarantees rounds it and keeps
+// the compiler from fusing it into the sum. The products of the indirect
+// predicates are converted the same way. Each operation then rounds once,
+// as the error bounds assume, on every architecture. Building with the
+// `fusedfilters` tag swaps in copies that fuse every product-plus-sum
+// step instead, for running the tests the way an unguarded build would
+// compile on arm64, ppc64le and s390x.
+
+// fusedFilters is set when built with the `fusedfilters` tag.
+const fusedFilters = false
+
+// orient2A is the stage A filter of `orient2` for the products acx*bcy
+// and acy*bcx. It returns the rounded determinant and whether its sign is
+// certain, and otherwise the detsum expected by the adaptive
+// implementation.
+func orient2A(acx, bcy, acy, bcx float64) (det, detsum float64, ok bool) {
+	detleft, detright := float64(acx*bcy), float64(acy*bcx)
+	det = detleft - detright
+
+	if detleft > 0.0 {
+		if detright <= 0.0 {
+			return det, 0, true
+		} else {
+			detsum = detleft + detright
+		}
+	} else if detleft < 0.0 {
+		if detright >= 0.0 {
+			return det, 0, true
+		} else {
+			detsum = -detleft - detright
+		}
+	} else {
+		return det, 0, true
+	}
+
+	errbound := ccwerrboundA * detsum
+	return det, detsum, (det >= errbound) || (-det >= errbound)
+}
+
+// orient3A is the stage A filter of `orient3`. It returns the rounded
+// determinant and whether its sign is certain, along with the permanent
+// expected by the adaptive implementation.
+func orient3A(
+	adx, bdx, cdx, ady, bdy, cdy, adz, bdz, cdz float64,
+) (det, permanent float64, ok bool) {
+
+	bdxcdy := float64(bdx * cdy)
+	cdxbdy := float64(cdx * bdy)
+
+	cdxady := float64(cdx * ady)
+	adxcdy := float64(adx * cdy)
+
+	adxbdy := float64(adx * bdy)
+	bdxady := float64(bdx * ady)
+
+	det =
+		float64(adz*(bdxcdy-cdxbdy)) +
+			float64(bdz*(cdxady-adxcdy)) +
+			float64(cdz*(adxbdy-bdxady))
+
+	permanent =
+		float64((math.Abs(bdxcdy)+math.Abs(cdxbdy))*math.Abs(adz)) +
+			float64((math.Abs(cdxady)+math.Abs(adxcdy))*math.Abs(bdz)) +
+			float64((math.Abs(adxbdy)+math.Abs(bdxady))*math.Abs(cdz))
+
+	errbound := o3derrboundA * permanent
+	return det, permanent, (det > errbound) || (-det > errbound)
+}
+
+// inCircleA is the stage A filter of `inCircle`. It returns the rounded
+// determinant and whether its sign is certain, along with the permanent
+// expected by the adaptive implementation.
+func inCircleA(
+	adx, bdx, cdx, ady, bdy, cdy float64,
+) (det, permanent float64, ok bool) {
+
+	bdxcdy := float64(bdx * cdy)
+	cdxbdy := float64(cdx * bdy)
+	alift := float64(adx*adx) + float64(ady*ady)
+
+	cdxady := float64(cdx * ady)
+	adxcdy := float64(adx * cdy)
+	blift := float64(bdx*bdx) + float64(bdy*bdy)
+
+	adxbdy := float64(adx * bdy)
+	bdxady := float64(bdx * ady)
+	clift := float64(cdx*cdx) + float64(cdy*cdy)
+
+	det =
+		float64(alift*(bdxcdy-cdxbdy)) +
+			float64(blift*(cdxady-adxcdy)) +
+			float64(clift*(adxbdy-bdxady))
+
+	permanent =
+		float64((math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift) +
+			float64((math.Abs(cdxady)+math.Abs(adxcdy))*blift) +
+			float64((math.Abs(adxbdy)+math.Abs(bdxady))*clift)
+
+	errbound := iccerrboundA * permanent
+	return det, permanent, (det > errbound) || (-det > errbound)
+}
+
+// inSphereA is the stage A filter of `inSphere`. It returns the rounded
+// determinant and whether its sign is certain, along with the permanent
+// expected by the adaptive implementation.
+func inSphereA(
+	aex, bex, cex, dex float64,
+	aey, bey, cey, dey float64,
+	aez, bez, cez, dez float64,
+) (det, permanent float64, ok bool) {
+
+	aexbey := float64(aex * bey)
+	bexaey := float64(bex * aey)
+	ab := aexbey - bexaey
+	bexcey := float64(bex * cey)
+	cexbey := float64(cex * bey)
+	bc := bexcey - cexbey
+	cexdey := float64(cex * dey)
+	dexcey := float64(dex * cey)
+	cd := cexdey - dexcey
+	dexaey := float64(dex * aey)
+	aexdey := float64(aex * dey)
+	da := dexaey - aexdey
+
+	aexcey := float64(aex * cey)
+	cexaey := float64(cex * aey)
+	ac := aexcey - cexaey
+	bexdey := float64(bex * dey)
+	dexbey := float64(dex * bey)
+	bd := bexdey - dexbey
+
+	abc := float64(aez*bc) - float64(bez*ac) + float64(cez*ab)
+	bcd := float64(bez*cd) - float64(cez*bd) + float64(dez*bc)
+	cda := float64(cez*da) + float64(dez*ac) + float64(aez*cd)
+	dab := float64(dez*ab) + float64(aez*bd) + float64(bez*da)
+
+	alift := float64(aex*aex) + float64(aey*aey) + float64(aez*aez)
+	blift := float64(bex*bex) + float64(bey*bey) + float64(bez*bez)
+	clift := float64(cex*cex) + float64(cey*cey) + float64(cez*cez)
+	dlift := float64(dex*dex) + float64(dey*dey) + float64(dez*dez)
+
+	det = (float64(dlift*abc) - float64(clift*dab)) + (float64(blift*cda) - float64(alift*bcd))
+
+	aezplus := math.Abs(aez)
+	bezplus := math.Abs(bez)
+	cezplus := math.Abs(cez)
+	dezplus := math.Abs(dez)
+	aexbeyplus := math.Abs(aexbey)
+	bexaeyplus := math.Abs(bexaey)
+	bexceyplus := math.Abs(bexcey)
+	cexbeyplus := math.Abs(cexbey)
+	cexdeyplus := math.Abs(cexdey)
+	dexceyplus := math.Abs(dexcey)
+	dexaeyplus := math.Abs(dexaey)
+	aexdeyplus := math.Abs(aexdey)
+	aexceyplus := math.Abs(aexcey)
+	cexaeyplus := math.Abs(cexaey)
+	bexdeyplus := math.Abs(bexdey)
+	dexbeyplus := math.Abs(dexbey)
+	permanent =
+		float64((float64((cexdeyplus+dexceyplus)*bezplus)+
+			float64((dexbeyplus+bexdeyplus)*cezplus)+
+			float64((bexceyplus+cexbeyplus)*dezplus))*
+			alift) +
+			float64((float64((dexaeyplus+aexdeyplus)*cezplus)+
+				float64((aexceyplus+cexaeyplus)*dezplus)+
+				float64((cexdeyplus+dexceyplus)*aezplus))*
+				blift) +
+			float64((float64((aexbeyplus+bexaeyplus)*dezplus)+
+				float64((bexdeyplus+dexbeyplus)*aezplus)+
+				float64((dexaeyplus+aexdeyplus)*bezplus))*
+				clift) +
+			float64((float64((bexceyplus+cexbeyplus)*aezplus)+
+				float64((cexaeyplus+aexceyplus)*bezplus)+
+				float64((aexbeyplus+bexaeyplus)*cezplus))*
+				dlift)
+	errbound := isperrboundA * permanent
+	return det, permanent, (det > errbound) || (-det > errbound)
+}
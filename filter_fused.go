@@ -0,0 +1,171 @@
+//go:build fusedfilters
+// +build fusedfilters
+
+package robust
+
+import "math"
+
+// The stage A filters with every product-plus-sum step fused by
+// `math.FMA`, as an architecture that fuses them would compile the filters
+// without the conversions that round each product. They're only built
+// with the `fusedfilters` tag, which runs the tests against them with
+//
+//	go test -tags fusedfilters ./...
+//
+// The products that are also used on their own, for the signs and the
+// permanents, are still rounded like the compiler would round them.
+
+// fusedFilters is set when built with the `fusedfilters` tag.
+const fusedFilters = true
+
+// orient2A is the stage A filter of `orient2` with the products acx*bcy
+// fused into det and detsum.
+func orient2A(acx, bcy, acy, bcx float64) (det, detsum float64, ok bool) {
+	detleft, detright := acx*bcy, acy*bcx
+	det = math.FMA(acx, bcy, -detright)
+
+	if detleft > 0.0 {
+		if detright <= 0.0 {
+			return det, 0, true
+		} else {
+			detsum = math.FMA(acx, bcy, detright)
+		}
+	} else if detleft < 0.0 {
+		if detright >= 0.0 {
+			return det, 0, true
+		} else {
+			detsum = math.FMA(-acx, bcy, -detright)
+		}
+	} else {
+		return det, 0, true
+	}
+
+	errbound := ccwerrboundA * detsum
+	return det, detsum, (det >= errbound) || (-det >= errbound)
+}
+
+// orient3A is the stage A filter of `orient3` with its minors and sums of
+// products fused.
+func orient3A(
+	adx, bdx, cdx, ady, bdy, cdy, adz, bdz, cdz float64,
+) (det, permanent float64, ok bool) {
+
+	bdxcdy := bdx * cdy
+	cdxbdy := cdx * bdy
+
+	cdxady := cdx * ady
+	adxcdy := adx * cdy
+
+	adxbdy := adx * bdy
+	bdxady := bdx * ady
+
+	det = math.FMA(cdz, math.FMA(adx, bdy, -bdxady),
+		math.FMA(bdz, math.FMA(cdx, ady, -adxcdy),
+			adz*math.FMA(bdx, cdy, -cdxbdy)))
+
+	permanent = math.FMA(math.Abs(adxbdy)+math.Abs(bdxady), math.Abs(cdz),
+		math.FMA(math.Abs(cdxady)+math.Abs(adxcdy), math.Abs(bdz),
+			(math.Abs(bdxcdy)+math.Abs(cdxbdy))*math.Abs(adz)))
+
+	errbound := o3derrboundA * permanent
+	return det, permanent, (det > errbound) || (-det > errbound)
+}
+
+// inCircleA is the stage A filter of `inCircle` with its minors, lifts
+// and sums of products fused.
+func inCircleA(
+	adx, bdx, cdx, ady, bdy, cdy float64,
+) (det, permanent float64, ok bool) {
+
+	bdxcdy := bdx * cdy
+	cdxbdy := cdx * bdy
+	alift := math.FMA(ady, ady, adx*adx)
+
+	cdxady := cdx * ady
+	adxcdy := adx * cdy
+	blift := math.FMA(bdy, bdy, bdx*bdx)
+
+	adxbdy := adx * bdy
+	bdxady := bdx * ady
+	clift := math.FMA(cdy, cdy, cdx*cdx)
+
+	det = math.FMA(clift, math.FMA(adx, bdy, -bdxady),
+		math.FMA(blift, math.FMA(cdx, ady, -adxcdy),
+			alift*math.FMA(bdx, cdy, -cdxbdy)))
+
+	permanent = math.FMA(math.Abs(adxbdy)+math.Abs(bdxady), clift,
+		math.FMA(math.Abs(cdxady)+math.Abs(adxcdy), blift,
+			(math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift))
+
+	errbound := iccerrboundA * permanent
+	return det, permanent, (det > errbound) || (-det > errbound)
+}
+
+// inSphereA is the stage A filter of `inSphere` with its minors, lifts
+// and sums of products fused.
+func inSphereA(
+	aex, bex, cex, dex float64,
+	aey, bey, cey, dey float64,
+	aez, bez, cez, dez float64,
+) (det, permanent float64, ok bool) {
+
+	aexbey := aex * bey
+	bexaey := bex * aey
+	ab := math.FMA(aex, bey, -bexaey)
+	bexcey := bex * cey
+	cexbey := cex * bey
+	bc := math.FMA(bex, cey, -cexbey)
+	cexdey := cex * dey
+	dexcey := dex * cey
+	cd := math.FMA(cex, dey, -dexcey)
+	dexaey := dex * aey
+	aexdey := aex * dey
+	da := math.FMA(dex, aey, -aexdey)
+
+	aexcey := aex * cey
+	cexaey := cex * aey
+	ac := math.FMA(aex, cey, -cexaey)
+	bexdey := bex * dey
+	dexbey := dex * bey
+	bd := math.FMA(bex, dey, -dexbey)
+
+	abc := math.FMA(cez, ab, math.FMA(-bez, ac, aez*bc))
+	bcd := math.FMA(dez, bc, math.FMA(-cez, bd, bez*cd))
+	cda := math.FMA(aez, cd, math.FMA(dez, ac, cez*da))
+	dab := math.FMA(bez, da, math.FMA(aez, bd, dez*ab))
+
+	alift := math.FMA(aez, aez, math.FMA(aey, aey, aex*aex))
+	blift := math.FMA(bez, bez, math.FMA(bey, bey, bex*bex))
+	clift := math.FMA(cez, cez, math.FMA(cey, cey, cex*cex))
+	dlift := math.FMA(dez, dez, math.FMA(dey, dey, dex*dex))
+
+	det = math.FMA(-clift, dab, dlift*abc) + math.FMA(-alift, bcd, blift*cda)
+
+	aezplus := math.Abs(aez)
+	bezplus := math.Abs(bez)
+	cezplus := math.Abs(cez)
+	dezplus := math.Abs(dez)
+	aexbeyplus := math.Abs(aexbey)
+	bexaeyplus := math.Abs(bexaey)
+	bexceyplus := math.Abs(bexcey)
+	cexbeyplus := math.Abs(cexbey)
+	cexdeyplus := math.Abs(cexdey)
+	dexceyplus := math.Abs(dexcey)
+	dexaeyplus := math.Abs(dexaey)
+	aexdeyplus := math.Abs(aexdey)
+	aexceyplus := math.Abs(aexcey)
+	cexaeyplus := math.Abs(cexaey)
+	bexdeyplus := math.Abs(bexdey)
+	dexbeyplus := math.Abs(dexbey)
+	aperm := math.FMA(bexceyplus+cexbeyplus, dezplus,
+		math.FMA(dexbeyplus+bexdeyplus, cezplus, (cexdeyplus+dexceyplus)*bezplus))
+	bperm := math.FMA(cexdeyplus+dexceyplus, aezplus,
+		math.FMA(aexceyplus+cexaeyplus, dezplus, (dexaeyplus+aexdeyplus)*cezplus))
+	cperm := math.FMA(dexaeyplus+aexdeyplus, bezplus,
+		math.FMA(bexdeyplus+dexbeyplus, aezplus, (aexbeyplus+bexaeyplus)*dezplus))
+	dperm := math.FMA(aexbeyplus+bexaeyplus, cezplus,
+		math.FMA(cexaeyplus+aexceyplus, bezplus, (bexceyplus+cexbeyplus)*aezplus))
+	permanent = math.FMA(dperm, dlift, math.FMA(cperm, clift, math.FMA(bperm, blift, aperm*alift)))
+	errbound := isperrboundA * permanent
+	return det, permanent, (det > errbound) || (-det > errbound)
+}
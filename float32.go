@@ -0,0 +1,125 @@
+package robust
+
+// double orient3dadapt(double *pa, double *pb, double *pc, double *pd, double permanent);
+// double incircleadapt(double *pa, double *pb, double *pc, double *pd, double permanent);
+// double insphereadapt(double *pa, double *pb, double *pc, double *pd, double *pe, double permanent);
+import "C"
+
+// The float32 predicates convert their inputs to float64, which is exact,
+// so they return the same results as the float64 predicates on the
+// converted points. The conversions stay in registers for the stage A
+// filters and the points are only copied into float64 arrays for the
+// adaptive implementations when the filters can't decide.
+
+// Orient2F32 is similar to `Orient2` but takes `[]float32` points. The
+// products of two float32 values are exact in float64, so when the filter
+// fails the determinant is expanded into its six coordinate products and
+// summed exactly without calling the adaptive implementation.
+//
+// Each slice parameter must contain at least 2 values.
+func Orient2F32(a, b, c []float32) float64 {
+	ax, ay := float64(a[0]), float64(a[1])
+	bx, by := float64(b[0]), float64(b[1])
+	cx, cy := float64(c[0]), float64(c[1])
+
+	det, _, ok := orient2A(ax-cx, by-cy, ay-cy, bx-cx)
+	if ok {
+		return det
+	}
+
+	// Sum the products with Shewchuk's Grow-Expansion, eliminating zero
+	// components, so the last component has the sign of the total.
+	terms := [6]float64{ax * by, -(ax * cy), -(cx * by), -(ay * bx), ay * cx, cy * bx}
+	var h [6]float64
+	n := 0
+	for _, q := range terms {
+		m := 0
+		for _, e := range h[:n] {
+			var hh float64
+			q, hh = twoSum(q, e)
+			if hh != 0 {
+				h[m] = hh
+				m++
+			}
+		}
+		if q != 0 {
+			h[m] = q
+			m++
+		}
+		n = m
+	}
+	if n == 0 {
+		return 0
+	}
+	return h[n-1]
+}
+
+// Orient3F32 is similar to `Orient3` but takes `[]float32` points.
+//
+// Each slice parameter must contain at least 3 values.
+func Orient3F32(a, b, c, d []float32) float64 {
+	dx, dy, dz := float64(d[0]), float64(d[1]), float64(d[2])
+	det, permanent, ok := orient3A(
+		float64(a[0])-dx, float64(b[0])-dx, float64(c[0])-dx,
+		float64(a[1])-dy, float64(b[1])-dy, float64(c[1])-dy,
+		float64(a[2])-dz, float64(b[2])-dz, float64(c[2])-dz,
+	)
+	if ok {
+		return det
+	}
+
+	p := [4][3]float64{f64x3(a), f64x3(b), f64x3(c), f64x3(d)}
+	return float64(C.orient3dadapt(
+		(*C.double)(&p[0][0]), (*C.double)(&p[1][0]), (*C.double)(&p[2][0]), (*C.double)(&p[3][0]),
+		C.double(permanent),
+	))
+}
+
+// InCircleF32 is similar to `InCircle` but takes `[]float32` points.
+//
+// Each slice parameter must contain at least 2 values.
+func InCircleF32(a, b, c, d []float32) float64 {
+	dx, dy := float64(d[0]), float64(d[1])
+	det, permanent, ok := inCircleA(
+		float64(a[0])-dx, float64(b[0])-dx, float64(c[0])-dx,
+		float64(a[1])-dy, float64(b[1])-dy, float64(c[1])-dy,
+	)
+	if ok {
+		return det
+	}
+
+	p := [4][2]float64{f64x2(a), f64x2(b), f64x2(c), f64x2(d)}
+	return float64(C.incircleadapt(
+		(*C.double)(&p[0][0]), (*C.double)(&p[1][0]), (*C.double)(&p[2][0]), (*C.double)(&p[3][0]),
+		C.double(permanent),
+	))
+}
+
+// InSphereF32 is similar to `InSphere` but takes `[]float32` points.
+//
+// Each slice parameter must contain at least 3 values.
+func InSphereF32(a, b, c, d, e []float32) float64 {
+	ex, ey, ez := float64(e[0]), float64(e[1]), float64(e[2])
+	det, permanent, ok := inSphereA(
+		float64(a[0])-ex, float64(b[0])-ex, float64(c[0])-ex, float64(d[0])-ex,
+		float64(a[1])-ey, float64(b[1])-ey, float64(c[1])-ey, float64(d[1])-ey,
+		float64(a[2])-ez, float64(b[2])-ez, float64(c[2])-ez, float64(d[2])-ez,
+	)
+	if ok {
+		return det
+	}
+
+	p := [5][3]float64{f64x3(a), f64x3(b), f64x3(c), f64x3(d), f64x3(e)}
+	return float64(C.insphereadapt(
+		(*C.double)(&p[0][0]), (*C.double)(&p[1][0]), (*C.double)(&p[2][0]), (*C.double)(&p[3][0]), (*C.double)(&p[4][0]),
+		C.double(permanent),
+	))
+}
+
+func f64x2(p []float32) [2]float64 {
+	return [2]float64{float64(p[0]), float64(p[1])}
+}
+
+func f64x3(p []float32) [3]float64 {
+	return [3]float64{float64(p[0]), float64(p[1]), float64(p[2])}
+}
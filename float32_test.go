@@ -0,0 +1,135 @@
+package robust_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// f32 rounds the points to float32 and returns them along with their
+// exact float64 conversions.
+func f32(pts [][]float64) ([][]float32, [][]float64) {
+	p32 := make([][]float32, len(pts))
+	p64 := make([][]float64, len(pts))
+	for i, p := range pts {
+		for _, x := range p {
+			p32[i] = append(p32[i], float32(x))
+			p64[i] = append(p64[i], float64(float32(x)))
+		}
+	}
+	return p32, p64
+}
+
+// checkF32 compares every float32 predicate with its float64 counterpart
+// on the converted points.
+func checkF32(t *testing.T, pred string, pts [][]float64) {
+	t.Helper()
+	p, q := f32(pts)
+	var got, want float64
+	switch pred {
+	case "orient2":
+		got, want = robust.Orient2F32(p[0], p[1], p[2]), robust.Orient2(q[0], q[1], q[2])
+	case "orient3":
+		got, want = robust.Orient3F32(p[0], p[1], p[2], p[3]), robust.Orient3(q[0], q[1], q[2], q[3])
+	case "incircle":
+		got, want = robust.InCircleF32(p[0], p[1], p[2], p[3]), robust.InCircle(q[0], q[1], q[2], q[3])
+	case "insphere":
+		got, want = robust.InSphereF32(p[0], p[1], p[2], p[3], p[4]), robust.InSphere(q[0], q[1], q[2], q[3], q[4])
+	}
+	if sign(got) != sign(want) {
+		t.Errorf("%s%v: want sign(%g); got sign(%g)", pred, p, want, got)
+	}
+}
+
+func Test_F32Fixtures(t *testing.T) {
+	for _, f := range []struct {
+		pred, file string
+		dim, n     int
+	}{
+		{"orient2", "orient2.txt", 2, 3},
+		{"orient3", "orient3.txt", 3, 4},
+		{"incircle", "incircle.txt", 2, 4},
+		{"insphere", "insphere.txt", 3, 5},
+	} {
+		for _, tt := range loadCases(t, f.file, f.dim*f.n) {
+			checkF32(t, f.pred, points(tt.args, f.dim))
+		}
+	}
+}
+
+// Test_F32Degenerate builds exactly degenerate float32 inputs far from
+// the origin and nudges one coordinate by an ulp, so that the filters
+// fail and the fallbacks decide.
+func Test_F32Degenerate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	nudge := func(pts [][]float64) [][]float64 {
+		x := float32(pts[0][0])
+		switch rng.Intn(3) {
+		case 0:
+			x = math.Nextafter32(x, float32(math.Inf(1)))
+		case 1:
+			x = math.Nextafter32(x, float32(math.Inf(-1)))
+		}
+		pts[0][0] = float64(x)
+		return pts
+	}
+	// Small integers scaled by a power of two and offset within float32
+	// precision stay exact.
+	coord := func(k int) float64 { return float64(rng.Intn(k) - k/2) }
+	for iter := 0; iter < 2000; iter++ {
+		scale := math.Ldexp(1, rng.Intn(40)-20)
+		off := []float64{coord(1<<12) * scale * 64, coord(1<<12) * scale * 64, coord(1<<12) * scale * 64}
+		at := func(v ...float64) []float64 {
+			p := make([]float64, len(v))
+			for k := range v {
+				p[k] = off[k] + v[k]*scale
+			}
+			return p
+		}
+
+		dx, dy := coord(16), coord(16)
+		checkF32(t, "orient2", nudge([][]float64{at(0, 0), at(dx, dy), at(2*dx, 2*dy)}))
+
+		ux, uy, uz := coord(8), coord(8), coord(8)
+		vx, vy, vz := coord(8), coord(8), coord(8)
+		checkF32(t, "orient3", nudge([][]float64{
+			at(0, 0, 0), at(ux, uy, uz), at(vx, vy, vz), at(ux+vx, uy+vy, uz+vz),
+		}))
+
+		// Integer points on circles and spheres of radius 5
+		checkF32(t, "incircle", nudge([][]float64{at(5, 0), at(3, 4), at(-4, 3), at(0, -5)}))
+		checkF32(t, "insphere", nudge([][]float64{
+			at(5, 0, 0), at(0, 5, 0), at(0, 0, 5), at(-3, -4, 0), at(0, 3, -4),
+		}))
+	}
+}
+
+func Test_Orient2F32Allocs(t *testing.T) {
+	a, b, c := []float32{1 << 20, 3}, []float32{1<<20 + 1, 5}, []float32{1<<20 + 2, 7}
+	allocs := testing.AllocsPerRun(100, func() {
+		result = robust.Orient2F32(a, b, c)
+	})
+	if allocs != 0 {
+		t.Errorf("want 0 allocs; got %g", allocs)
+	}
+}
+
+func Benchmark_Orient2F32(b *testing.B) {
+	fixtures := loadCases(b, "orient2.txt", 6)
+	tests := make([][3][]float32, len(fixtures))
+	for i, tt := range fixtures {
+		p, _ := f32(points(tt.args, 2))
+		tests[i] = [3][]float32{p[0], p[1], p[2]}
+	}
+
+	b.ResetTimer()
+	var res float64
+	for n := 0; n < b.N; n++ {
+		for _, arr := range tests {
+			res = robust.Orient2F32(arr[0], arr[1], arr[2])
+		}
+	}
+	result = res
+}
@@ -0,0 +1,35 @@
+//go:build go1.14
+// +build go1.14
+
+package robust
+
+import (
+	"math"
+	"runtime"
+
+	"neilpa.me/cgo-shewchuk-robust/internal/cpu"
+)
+
+// fmaProducts selects twoProductFMA for the exact products. It's set when
+// FMA is done in hardware, since the software fallback of `math.FMA` is
+// much slower than splitting. Both give the same products, so the choice
+// only affects speed and isn't exposed; it's only changed by tests.
+var fmaProducts = hardwareFMA()
+
+func hardwareFMA() bool {
+	switch runtime.GOARCH {
+	case "amd64":
+		return cpu.X86.HasFMA
+	case "arm64", "ppc64", "ppc64le", "s390x":
+		return true
+	}
+	return false
+}
+
+// twoProductFMA computes x + y == a * b exactly, getting the rounding
+// error of the product from a single fused multiply-add.
+func twoProductFMA(a, b float64) (x, y float64) {
+	x = float64(a * b)
+	y = math.FMA(a, b, -x)
+	return x, y
+}
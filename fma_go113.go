@@ -0,0 +1,11 @@
+//go:build !go1.14
+// +build !go1.14
+
+package robust
+
+// `math.FMA` was added in Go 1.14, so older versions always split.
+const fmaProducts = false
+
+func twoProductFMA(a, b float64) (x, y float64) {
+	return twoProductSplit(a, b)
+}
@@ -0,0 +1,137 @@
+//go:build go1.14
+// +build go1.14
+
+package robust
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// withProducts evaluates fn with the exact products computed by FMA or by
+// splitting.
+func withProducts(fma bool, fn func() []float64) []float64 {
+	defer func(saved bool) { fmaProducts = saved }(fmaProducts)
+	fmaProducts = fma
+	return fn()
+}
+
+// randomFloat returns a value with a random mantissa and sign, and an
+// exponent in [-scale, scale).
+func randomFloat(rng *rand.Rand, scale int) float64 {
+	x := math.Ldexp(rng.Float64()+0.5, rng.Intn(2*scale)-scale)
+	if rng.Intn(2) == 0 {
+		x = -x
+	}
+	return x
+}
+
+// randomExpansion returns the exact sum of n products of random values,
+// which gives expansions of various lengths and magnitude spreads.
+func randomExpansion(rng *rand.Rand, n int) []float64 {
+	e := []float64{0}
+	for i := 0; i < n; i++ {
+		e = sumExpansion(e, productExpansion(randomFloat(rng, 40), randomFloat(rng, 40)))
+	}
+	return e
+}
+
+// sink keeps the benchmarked expansions alive.
+var sink []float64
+
+func sameBits(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Float64bits(a[i]) != math.Float64bits(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func Test_TwoProductFMA(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	inf, max := math.Inf(1), math.MaxFloat64
+	pairs := [][2]float64{
+		{0, 0}, {0, -1}, {1, 1}, {-3, 7},
+		{1 << 26, 1 << 26}, {1<<27 - 1, 1<<27 + 1},
+		{1 + 0x1p-52, 1 - 0x1p-53}, {0.1, 0.2}, {math.Pi, math.E},
+		// Too large to split, or with partial products that overflow
+		{0x1p996, 3}, {0x1.fffffffp996, 1 - 0x1p-53}, {max, 1e-300}, {-1e305, 1},
+		{max, 1 - 0x1p-53}, {0x1.fffffffp511, -0x1.fffffffp511}, {1e308, 1e-10},
+		// Overflowing products and infinite operands
+		{max, 2}, {-1e200, 1e200}, {inf, 2}, {inf, 0}, {-inf, inf},
+	}
+	for i := 0; i < 100000; i++ {
+		pairs = append(pairs, [2]float64{randomFloat(rng, 500), randomFloat(rng, 500)})
+		// Below 2^-969 the rounding errors underflow
+		if a, b := randomFloat(rng, 1024), randomFloat(rng, 1024); math.Abs(a*b) >= 0x1p-969 {
+			pairs = append(pairs, [2]float64{a, b})
+		}
+	}
+	same := func(x, y float64) bool {
+		return math.Float64bits(x) == math.Float64bits(y) || math.IsNaN(x) && math.IsNaN(y)
+	}
+	defer func(saved bool) { fmaProducts = saved }(fmaProducts)
+	fmaProducts = false
+	for _, p := range pairs {
+		x1, y1 := twoProductFMA(p[0], p[1])
+		x2, y2 := twoProductSplit(p[0], p[1])
+		if !same(x1, x2) || !same(y1, y2) {
+			t.Fatalf("%g * %g: fma %g + %g; split %g + %g", p[0], p[1], x1, y1, x2, y2)
+		}
+		bhi, blo := split(p[1])
+		x2, y2 = twoProductPresplit(p[0], p[1], bhi, blo)
+		if !same(x1, x2) || !same(y1, y2) {
+			t.Fatalf("%g * %g: fma %g + %g; presplit %g + %g", p[0], p[1], x1, y1, x2, y2)
+		}
+	}
+}
+
+func Test_FMAExpansions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		e, f := randomExpansion(rng, 1+rng.Intn(4)), randomExpansion(rng, 1+rng.Intn(4))
+		b := randomFloat(rng, 40)
+		// Nearly parallel segments, whose intersection needs long products.
+		p := [2][]float64{{0, 0}, {randomFloat(rng, 10), randomFloat(rng, 10)}}
+		d := math.Ldexp(randomFloat(rng, 1), -40)
+		q := [2][]float64{{d, -d}, {p[1][0] + d, p[1][1] - d}}
+
+		for _, tt := range []struct {
+			label string
+			fn    func() []float64
+		}{
+			{"scale", func() []float64 { return scaleExpansion(e, b) }},
+			{"mul", func() []float64 { return mulExpansion(e, f) }},
+			{"lpi2", func() []float64 { w, n := lpi2Exact(p, q); return append(w, n...) }},
+		} {
+			fma, split := withProducts(true, tt.fn), withProducts(false, tt.fn)
+			if !sameBits(fma, split) {
+				t.Fatalf("%s %d: fma %v; split %v", tt.label, i, fma, split)
+			}
+		}
+	}
+}
+
+func Benchmark_MulExpansion(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	e, f := randomExpansion(rng, 4), randomExpansion(rng, 4)
+	for _, fma := range []bool{false, true} {
+		label := "split"
+		if fma {
+			label = "fma"
+		}
+		b.Run(label, func(b *testing.B) {
+			withProducts(fma, func() []float64 {
+				for n := 0; n < b.N; n++ {
+					sink = mulExpansion(e, f)
+				}
+				return nil
+			})
+		})
+	}
+}
@@ -0,0 +1,74 @@
+//go:build go1.21
+// +build go1.21
+
+package robust
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"testing"
+)
+
+// fusedAllowed lists the positions whose fused products are intended or
+// harmless. Edits to these files move them, which fails the test below
+// until the list is updated.
+var fusedAllowed = map[string]bool{
+	// The exact product of twoProductFMA
+	"fma.go:33": true,
+
+	// Constructions, which are rounded anyway
+	"enclose.go:74":  true,
+	"enclose.go:79":  true,
+	"enclose.go:141": true,
+	"enclose.go:245": true,
+	"slice.go:102":   true,
+	"slice.go:103":   true,
+	"slice.go:104":   true,
+
+	// The products of infinities and NaNs in Dot
+	"sum.go:52": true,
+
+	// The error bound constants, whose products of epsilon are exact
+	"robust.go:104": true,
+	"robust.go:105": true,
+	"robust.go:108": true,
+	"robust.go:114": true,
+	"robust.go:115": true,
+	"robust.go:116": true,
+	"robust.go:117": true,
+	"robust.go:118": true,
+	"robust.go:119": true,
+	"robust.go:120": true,
+	"robust.go:121": true,
+}
+
+// Test_FiltersUnfused checks that the package built with GOAMD64=v3, where
+// amd64 fuses products like arm64, ppc64le and s390x, only fuses the
+// products in fusedAllowed.
+func Test_FiltersUnfused(t *testing.T) {
+	gobin := filepath.Join(runtime.GOROOT(), "bin", "go")
+	cmd := exec.Command(gobin, "build", "-gcflags=-S", "-o", os.DevNull, ".")
+	cmd.Env = append(os.Environ(), "GOAMD64=v3")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("GOAMD64=v3 build: %v\n%s", err, out)
+	}
+
+	// Instructions are annotated with their source position, even when
+	// inlined into another function.
+	re := regexp.MustCompile(`\(([^()\[\]]+\.go):(\d+)[^()]*\)\s+VFN?M(ADD|SUB)\d*SD`)
+	seen := map[string]bool{}
+	for _, m := range re.FindAllSubmatch(out, -1) {
+		pos := filepath.Base(string(m[1])) + ":" + string(m[2])
+		if !fusedAllowed[pos] && !seen[pos] {
+			t.Errorf("%s: fused product", pos)
+		}
+		seen[pos] = true
+	}
+	if !seen["fma.go:33"] {
+		t.Error("fma.go:33: math.FMA isn't fused, so the assembly wasn't inspected")
+	}
+}
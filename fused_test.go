@@ -0,0 +1,142 @@
+package robust
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"neilpa.me/cgo-shewchuk-robust/internal/sign"
+)
+
+// Go may fuse x*y + z into a single FMA, which skips the rounding of the
+// product assumed by the error bounds. Running the whole suite under
+// `GOAMD64=v3 go test ./...` evaluates the shipped filters the way arm64,
+// ppc64le and s390x compile them, and `go test -tags fusedfilters ./...`
+// evaluates copies that fuse every product-plus-sum step. `Test_FilterSigns`
+// checks that the filters certify only correct signs either way.
+
+// near returns a random point on the unit sphere in dim dimensions, which
+// makes the points of the in-circle and in-sphere tests nearly cospherical.
+func near(rng *rand.Rand, dim int) []float64 {
+	p := make([]float64, dim)
+	r := 0.0
+	for i := range p {
+		p[i] = rng.NormFloat64()
+		r += p[i] * p[i]
+	}
+	for i := range p {
+		p[i] /= math.Sqrt(r)
+	}
+	return p
+}
+
+// affine returns the rounded point a + s(b-a) + t(c-a), which is nearly
+// collinear or coplanar with them.
+func affine(rng *rand.Rand, a, b, c []float64) []float64 {
+	s, u := rng.Float64()*4-2, rng.Float64()*4-2
+	p := make([]float64, len(a))
+	for i := range p {
+		p[i] = a[i] + s*(b[i]-a[i])
+		if c != nil {
+			p[i] += u * (c[i] - a[i])
+		}
+	}
+	return p
+}
+
+// Test_FilterSigns checks the signs certified by the stage A filters, and
+// those of the indirect predicates, whose filters are inlined, against
+// exact arithmetic on nearly degenerate inputs.
+func Test_FilterSigns(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 100000
+
+	check := func(t *testing.T, i int, ok bool, det float64, exact []float64) {
+		t.Helper()
+		if ok && sign.Of(det) != signExpansion(exact) {
+			t.Fatalf("case %d: filter certified %g; exact %v", i, det, exact)
+		}
+	}
+	t.Run("orient2", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			a, b := near(rng, 2), near(rng, 2)
+			c := affine(rng, a, b, nil)
+			det, _, ok := orient2A(a[0]-c[0], b[1]-c[1], a[1]-c[1], b[0]-c[0])
+			check(t, i, ok, det, Orient2Expansion(a, b, c))
+		}
+	})
+	t.Run("orient3", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			a, b, c := near(rng, 3), near(rng, 3), near(rng, 3)
+			d := affine(rng, a, b, c)
+			det, _, ok := orient3A(
+				a[0]-d[0], b[0]-d[0], c[0]-d[0],
+				a[1]-d[1], b[1]-d[1], c[1]-d[1],
+				a[2]-d[2], b[2]-d[2], c[2]-d[2],
+			)
+			check(t, i, ok, det, Orient3Expansion(a, b, c, d))
+		}
+	})
+	t.Run("incircle", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			a, b, c, d := near(rng, 2), near(rng, 2), near(rng, 2), near(rng, 2)
+			det, _, ok := inCircleA(a[0]-d[0], b[0]-d[0], c[0]-d[0], a[1]-d[1], b[1]-d[1], c[1]-d[1])
+			check(t, i, ok, det, InCircleExpansion(a, b, c, d))
+		}
+	})
+	t.Run("insphere", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			a, b, c, d, e := near(rng, 3), near(rng, 3), near(rng, 3), near(rng, 3), near(rng, 3)
+			det, _, ok := inSphereA(
+				a[0]-e[0], b[0]-e[0], c[0]-e[0], d[0]-e[0],
+				a[1]-e[1], b[1]-e[1], c[1]-e[1], d[1]-e[1],
+				a[2]-e[2], b[2]-e[2], c[2]-e[2], d[2]-e[2],
+			)
+			check(t, i, ok, det, InSphereExpansion(a, b, c, d, e))
+		}
+	})
+
+	// The indirect predicates fall back to their exact versions when the
+	// filters fail, so they differ only if a filter certifies a wrong sign.
+	exact := func(t *testing.T, i int, got, want float64) {
+		t.Helper()
+		if sign.Of(got) != sign.Of(want) {
+			t.Fatalf("case %d: got %g; exact %g", i, got, want)
+		}
+	}
+	t.Run("lpi2", func(t *testing.T) {
+		for i := 0; i < n/10; i++ {
+			x := near(rng, 2)
+			p := [2][]float64{affine(rng, x, near(rng, 2), nil), affine(rng, x, near(rng, 2), nil)}
+			q := [2][]float64{affine(rng, x, near(rng, 2), nil), affine(rng, x, near(rng, 2), nil)}
+			a := near(rng, 2)
+			b := affine(rng, a, x, nil)
+			exact(t, i, Orient2LPI(a, b, p, q), orient2LPIExact(a, b, p, q))
+			exact(t, i, ComparePointLPI2(i%2, x, p, q), comparePointLPI2Exact(i%2, x, p, q))
+			r := [2][]float64{affine(rng, x, near(rng, 2), nil), affine(rng, x, near(rng, 2), nil)}
+			exact(t, i, CompareLPI2(i%2, p, q, r, q), compareLPI2Exact(i%2, p, q, r, q))
+		}
+	})
+	t.Run("lpi3", func(t *testing.T) {
+		for i := 0; i < n/10; i++ {
+			x := near(rng, 3)
+			line := [2][]float64{affine(rng, x, near(rng, 3), nil), affine(rng, x, near(rng, 3), nil)}
+			plane := [3][]float64{x, affine(rng, x, near(rng, 3), nil), affine(rng, x, near(rng, 3), nil)}
+			a, b := near(rng, 3), near(rng, 3)
+			c := affine(rng, a, b, x)
+			exact(t, i, Orient3LPI(a, b, c, line, plane), orient3LPIExact(a, b, c, line, plane))
+		}
+	})
+	t.Run("tpi3", func(t *testing.T) {
+		plane := func(x []float64) [3][]float64 {
+			return [3][]float64{affine(rng, x, near(rng, 3), nil), affine(rng, x, near(rng, 3), nil), affine(rng, x, near(rng, 3), nil)}
+		}
+		for i := 0; i < n/10; i++ {
+			x := near(rng, 3)
+			p, q, r := plane(x), plane(x), plane(x)
+			a, b := near(rng, 3), near(rng, 3)
+			c := affine(rng, a, b, x)
+			exact(t, i, Orient3TPI(a, b, c, p, q, r), orient3TPIExact(a, b, c, p, q, r))
+		}
+	})
+}
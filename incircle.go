@@ -3,7 +3,6 @@ package robust
 // double incircle(double *pa, double *pb, double *pc, double *pd);
 // double incircleadapt(double *pa, double *pb, double *pc, double *pd, double permanent);
 import "C"
-import "math"
 
 // InCircle returns a positive value if the point d lies inside the
 // circle passing through a, b, and c; a negative value if it lies
@@ -38,6 +37,30 @@ func InCircleVec(a, b, c, d *XY) float64 {
 	)
 }
 
+// InCircleAt is similar to `InCircle` but takes the points at indices i,
+// j, k, and l of a buffer of interleaved points, where point i starts at
+// buf[i*stride]. The bounds of all four points are checked once up front.
+// The stride must be at least 2.
+func InCircleAt(buf []float64, stride, i, j, k, l int) float64 {
+	if n := len(buf); stride < 2 ||
+		!inAt(n, stride, 2, i) ||
+		!inAt(n, stride, 2, j) ||
+		!inAt(n, stride, 2, k) ||
+		!inAt(n, stride, 2, l) {
+		panicAt(buf, stride, 2, i, j, k, l)
+	}
+	i, j, k, l = i*stride, j*stride, k*stride, l*stride
+	pa := (*C.double)(&buf[i])
+	pb := (*C.double)(&buf[j])
+	pc := (*C.double)(&buf[k])
+	pd := (*C.double)(&buf[l])
+
+	return inCircle(pa, pb, pc, pd,
+		buf[i]-buf[l], buf[j]-buf[l], buf[k]-buf[l],
+		buf[i+1]-buf[l+1], buf[j+1]-buf[l+1], buf[k+1]-buf[l+1],
+	)
+}
+
 // InCirclePtr is the direct wrapper of `incircle` from `predicates.c`.
 // See `InCircle` for additional details.
 func InCirclePtr(a, b, c, d *float64) float64 {
@@ -53,33 +76,9 @@ func InCirclePtr(a, b, c, d *float64) float64 {
 func inCircle(pa, pb, pc, pd *C.double,
 	adx, bdx, cdx, ady, bdy, cdy float64,
 ) float64 {
-
-	bdxcdy := bdx * cdy
-	cdxbdy := cdx * bdy
-	alift := adx*adx + ady*ady
-
-	cdxady := cdx * ady
-	adxcdy := adx * cdy
-	blift := bdx*bdx + bdy*bdy
-
-	adxbdy := adx * bdy
-	bdxady := bdx * ady
-	clift := cdx*cdx + cdy*cdy
-
-	det :=
-		alift*(bdxcdy-cdxbdy) +
-			blift*(cdxady-adxcdy) +
-			clift*(adxbdy-bdxady)
-
-	permanent :=
-		(math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
-			(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
-			(math.Abs(adxbdy)+math.Abs(bdxady))*clift
-
-	errbound := iccerrboundA * permanent
-	if (det > errbound) || (-det > errbound) {
+	det, permanent, ok := inCircleA(adx, bdx, cdx, ady, bdy, cdy)
+	if ok {
 		return det
 	}
-
 	return float64(C.incircleadapt(pa, pb, pc, pd, C.double(permanent)))
 }
@@ -26,6 +26,9 @@ func Test_InCircle(t *testing.T) {
 
 			res = robust.InCircleVec((*robust.XY)(&va), (*robust.XY)(&vb), (*robust.XY)(&vc), (*robust.XY)(&vd))
 			assert(t, tt.sign, res)
+
+			res = robust.InCircleAt(interleave(2, a, b, c, d), 2, 3, 2, 1, 0)
+			assert(t, tt.sign, res)
 		})
 	}
 }
@@ -0,0 +1,384 @@
+package robust
+
+import "math"
+
+// Indirect predicates evaluate `Orient2` and `Orient3` where the last
+// point is only implicitly defined as the intersection of other
+// primitives, in the spirit of Attene's indirect predicates. The
+// intersection is never rounded. Instead it's kept in homogeneous form
+// (X, W) with exact polynomial coordinates, so that
+//
+//	sign(orient(..., X/W)) = sign(orient'(..., X - W·a)) · sign(W)
+//
+// where orient' is the (linear) orientation determinant translated to a.
+// Each predicate evaluates this with a floating-point filter, whose error
+// bound is derived from the depth of the expression, and falls back to
+// exact expansion arithmetic when the filter can't certify the sign.
+
+// Orient2LPI returns a positive value if the points a, b, and i occur in
+// counterclockwise order; a negative value if they occur in clockwise
+// order; and zero if they are collinear. The point i is the intersection
+// of the line through p[0] and p[1] with the line through q[0] and q[1].
+//
+// Zero is also returned if the two lines are parallel, in which case the
+// intersection point is undefined.
+//
+// Each slice parameter must contain at least 2 values.
+func Orient2LPI(a, b []float64, p, q [2][]float64) float64 {
+	ux, uy := p[1][0]-p[0][0], p[1][1]-p[0][1]
+	vx, vy := q[1][0]-q[0][0], q[1][1]-q[0][1]
+	wx, wy := q[0][0]-p[0][0], q[0][1]-p[0][1]
+	ex, ey := p[0][0]-a[0], p[0][1]-a[1]
+	bx, by := b[0]-a[0], b[1]-a[1]
+
+	// i = p0 + (N/W)(p1-p0) where W = u×v and N = w×v
+	uxvy, uyvx := float64(ux*vy), float64(uy*vx)
+	w := uxvy - uyvx
+	wperm := math.Abs(uxvy) + math.Abs(uyvx)
+	wxvy, wyvx := float64(wx*vy), float64(wy*vx)
+	n := wxvy - wyvx
+	nperm := math.Abs(wxvy) + math.Abs(wyvx)
+
+	xx := float64(w*ex) + float64(n*ux)
+	xperm := float64(wperm*math.Abs(ex)) + float64(nperm*math.Abs(ux))
+	xy := float64(w*ey) + float64(n*uy)
+	yperm := float64(wperm*math.Abs(ey)) + float64(nperm*math.Abs(uy))
+
+	det := float64(bx*xy) - float64(by*xx)
+	permanent := float64(math.Abs(bx)*yperm) + float64(math.Abs(by)*xperm)
+
+	if math.Abs(w) > lpi2errboundW*wperm {
+		errbound := lpi2errboundA * permanent
+		if (det > errbound) || (-det > errbound) {
+			if w < 0 {
+				return -det
+			}
+			return det
+		}
+	}
+
+	return orient2LPIExact(a, b, p, q)
+}
+
+// orient2LPIExact is the exact expansion arithmetic fallback for
+// `Orient2LPI`.
+func orient2LPIExact(a, b []float64, p, q [2][]float64) float64 {
+	ux, uy := diffExpansion(p[1][0], p[0][0]), diffExpansion(p[1][1], p[0][1])
+	vx, vy := diffExpansion(q[1][0], q[0][0]), diffExpansion(q[1][1], q[0][1])
+	wx, wy := diffExpansion(q[0][0], p[0][0]), diffExpansion(q[0][1], p[0][1])
+	ex, ey := diffExpansion(p[0][0], a[0]), diffExpansion(p[0][1], a[1])
+	bx, by := diffExpansion(b[0], a[0]), diffExpansion(b[1], a[1])
+
+	w := subExpansion(mulExpansion(ux, vy), mulExpansion(uy, vx))
+	if signExpansion(w) == 0 {
+		return 0
+	}
+	n := subExpansion(mulExpansion(wx, vy), mulExpansion(wy, vx))
+	xx := sumExpansion(mulExpansion(w, ex), mulExpansion(n, ux))
+	xy := sumExpansion(mulExpansion(w, ey), mulExpansion(n, uy))
+	det := subExpansion(mulExpansion(bx, xy), mulExpansion(by, xx))
+	return float64(signExpansion(w)) * det[len(det)-1]
+}
+
+// ComparePointLPI2 returns a positive value if the point e has a larger
+// coordinate along axis (0 for x, 1 for y) than the point i; a negative
+// value if it's smaller; and zero if they're equal. The point i is the
+// intersection of the line through p[0] and p[1] with the line through
+// q[0] and q[1].
+//
+// Zero is also returned if the two lines are parallel, in which case the
+// intersection point is undefined.
+//
+// Each slice parameter must contain at least 2 values.
+func ComparePointLPI2(axis int, e []float64, p, q [2][]float64) float64 {
+	w, wperm, n, nperm := lpi2(p, q)
+	uk := p[1][axis] - p[0][axis]
+	ek := p[0][axis] - e[axis]
+
+	// i[axis] - e[axis] = X/W
+	x := float64(ek*w) + float64(n*uk)
+	permanent := float64(math.Abs(ek)*wperm) + float64(nperm*math.Abs(uk))
+
+	if math.Abs(w) > lpi2errboundW*wperm {
+		errbound := cmpptlpi2errboundA * permanent
+		if (x > errbound) || (-x > errbound) {
+			if w < 0 {
+				return x
+			}
+			return -x
+		}
+	}
+
+	return comparePointLPI2Exact(axis, e, p, q)
+}
+
+// comparePointLPI2Exact is the exact expansion arithmetic fallback for
+// `ComparePointLPI2`.
+func comparePointLPI2Exact(axis int, e []float64, p, q [2][]float64) float64 {
+	w, n := lpi2Exact(p, q)
+	if signExpansion(w) == 0 {
+		return 0
+	}
+	uk := diffExpansion(p[1][axis], p[0][axis])
+	ek := diffExpansion(p[0][axis], e[axis])
+	x := sumExpansion(mulExpansion(ek, w), mulExpansion(n, uk))
+	return -float64(signExpansion(w)) * x[len(x)-1]
+}
+
+// CompareLPI2 returns a positive value if the point i has a larger
+// coordinate along axis (0 for x, 1 for y) than the point j; a negative
+// value if it's smaller; and zero if they're equal. The point i is the
+// intersection of the lines through p[0], p[1] and q[0], q[1], and j the
+// intersection of the lines through r[0], r[1] and s[0], s[1].
+//
+// Zero is also returned if either pair of lines is parallel, in which
+// case the intersection point is undefined.
+//
+// Each slice parameter must contain at least 2 values.
+func CompareLPI2(axis int, p, q, r, s [2][]float64) float64 {
+	// Both points are taken relative to p[0], so that i[axis] - j[axis]
+	// = X1/W1 - X2/W2 with X1 = N1·u1 and X2 = e·W2 + N2·u2.
+	w1, w1perm, n1, n1perm := lpi2(p, q)
+	w2, w2perm, n2, n2perm := lpi2(r, s)
+	u1 := p[1][axis] - p[0][axis]
+	u2 := r[1][axis] - r[0][axis]
+	ek := r[0][axis] - p[0][axis]
+
+	x1 := float64(n1 * u1)
+	x1perm := float64(n1perm * math.Abs(u1))
+	x2 := float64(ek*w2) + float64(n2*u2)
+	x2perm := float64(math.Abs(ek)*w2perm) + float64(n2perm*math.Abs(u2))
+
+	det := float64(x1*w2) - float64(x2*w1)
+	permanent := float64(x1perm*w2perm) + float64(x2perm*w1perm)
+
+	if math.Abs(w1) > lpi2errboundW*w1perm && math.Abs(w2) > lpi2errboundW*w2perm {
+		errbound := cmplpi2errboundA * permanent
+		if (det > errbound) || (-det > errbound) {
+			if (w1 < 0) != (w2 < 0) {
+				return -det
+			}
+			return det
+		}
+	}
+
+	return compareLPI2Exact(axis, p, q, r, s)
+}
+
+// compareLPI2Exact is the exact expansion arithmetic fallback for
+// `CompareLPI2`.
+func compareLPI2Exact(axis int, p, q, r, s [2][]float64) float64 {
+	w1, n1 := lpi2Exact(p, q)
+	w2, n2 := lpi2Exact(r, s)
+	if signExpansion(w1) == 0 || signExpansion(w2) == 0 {
+		return 0
+	}
+	u1 := diffExpansion(p[1][axis], p[0][axis])
+	u2 := diffExpansion(r[1][axis], r[0][axis])
+	ek := diffExpansion(r[0][axis], p[0][axis])
+
+	x1 := mulExpansion(n1, u1)
+	x2 := sumExpansion(mulExpansion(ek, w2), mulExpansion(n2, u2))
+	det := subExpansion(mulExpansion(x1, w2), mulExpansion(x2, w1))
+	return float64(signExpansion(w1)*signExpansion(w2)) * det[len(det)-1]
+}
+
+// lpi2 returns the denominator W = u×v and numerator N = w×v of the
+// parameter along p of its intersection with q, where u = p[1]-p[0],
+// v = q[1]-q[0] and w = q[0]-p[0], along with their permanents.
+func lpi2(p, q [2][]float64) (w, wperm, n, nperm float64) {
+	ux, uy := p[1][0]-p[0][0], p[1][1]-p[0][1]
+	vx, vy := q[1][0]-q[0][0], q[1][1]-q[0][1]
+	wx, wy := q[0][0]-p[0][0], q[0][1]-p[0][1]
+
+	uxvy, uyvx := float64(ux*vy), float64(uy*vx)
+	wxvy, wyvx := float64(wx*vy), float64(wy*vx)
+	w = uxvy - uyvx
+	wperm = math.Abs(uxvy) + math.Abs(uyvx)
+	n = wxvy - wyvx
+	nperm = math.Abs(wxvy) + math.Abs(wyvx)
+	return w, wperm, n, nperm
+}
+
+// lpi2Exact is the exact expansion arithmetic version of `lpi2`.
+func lpi2Exact(p, q [2][]float64) (w, n []float64) {
+	ux, uy := diffExpansion(p[1][0], p[0][0]), diffExpansion(p[1][1], p[0][1])
+	vx, vy := diffExpansion(q[1][0], q[0][0]), diffExpansion(q[1][1], q[0][1])
+	wx, wy := diffExpansion(q[0][0], p[0][0]), diffExpansion(q[0][1], p[0][1])
+
+	w = subExpansion(mulExpansion(ux, vy), mulExpansion(uy, vx))
+	n = subExpansion(mulExpansion(wx, vy), mulExpansion(wy, vx))
+	return w, n
+}
+
+// Orient3LPI returns a positive value if the point i lies below the
+// plane passing through a, b, and c, following the conventions of
+// `Orient3`. The point i is the intersection of the line through line[0]
+// and line[1] with the plane through plane[0], plane[1], and plane[2].
+//
+// Zero is also returned if the line is parallel to the plane or the
+// plane is degenerate, in which case the intersection point is undefined.
+//
+// Each slice parameter must contain at least 3 values.
+func Orient3LPI(a, b, c []float64, line [2][]float64, plane [3][]float64) float64 {
+	p, q := line[0], line[1]
+	r, s, t := plane[0], plane[1], plane[2]
+
+	// i = p + (N/W)(q-p) where m is the plane normal, N = m·(r-p), W = m·(q-p)
+	m, mperm := crossPerm(sub3(s, r), sub3(t, r))
+	qp, rp := sub3(q, p), sub3(r, p)
+	w, wperm := dotPerm(m, mperm, qp)
+	n, nperm := dotPerm(m, mperm, rp)
+
+	pa := sub3(p, a)
+	var x, xperm [3]float64
+	for i := 0; i < 3; i++ {
+		x[i] = float64(w*pa[i]) + float64(n*qp[i])
+		xperm[i] = float64(wperm*math.Abs(pa[i])) + float64(nperm*math.Abs(qp[i]))
+	}
+
+	nabc, nabcperm := crossPerm(sub3(b, a), sub3(c, a))
+	det, permanent := dotPerm3(nabc, nabcperm, x, xperm)
+
+	if math.Abs(w) > lpi3errboundW*wperm {
+		errbound := lpi3errboundA * permanent
+		if (det > errbound) || (-det > errbound) {
+			if w < 0 {
+				return det
+			}
+			return -det
+		}
+	}
+
+	return orient3LPIExact(a, b, c, line, plane)
+}
+
+// orient3LPIExact is the exact expansion arithmetic fallback for
+// `Orient3LPI`.
+func orient3LPIExact(a, b, c []float64, line [2][]float64, plane [3][]float64) float64 {
+	p, q := line[0], line[1]
+	r, s, t := plane[0], plane[1], plane[2]
+
+	m := crossExpansion(diffVector(s, r), diffVector(t, r))
+	qp := diffVector(q, p)
+	w := dotExpansion(m, qp)
+	if signExpansion(w) == 0 {
+		return 0
+	}
+	n := dotExpansion(m, diffVector(r, p))
+	x := sumVector(scaleVector(diffVector(p, a), w), scaleVector(qp, n))
+
+	nabc := crossExpansion(diffVector(b, a), diffVector(c, a))
+	det := dotExpansion(nabc, x)
+	return -float64(signExpansion(w)) * det[len(det)-1]
+}
+
+// Orient3TPI returns a positive value if the point i lies below the
+// plane passing through a, b, and c, following the conventions of
+// `Orient3`. The point i is the common intersection of the three planes
+// through the points p, q, and r respectively.
+//
+// Zero is also returned if the planes don't meet in a single point, in
+// which case the intersection point is undefined.
+//
+// Each slice parameter must contain at least 3 values.
+func Orient3TPI(a, b, c []float64, p, q, r [3][]float64) float64 {
+	// i = a + (d1 n2×n3 + d2 n3×n1 + d3 n1×n2) / W with W = n1·(n2×n3)
+	// and the plane offsets dk = nk·(pk - a) taken relative to a.
+	n1, n1perm := crossPerm(sub3(p[1], p[0]), sub3(p[2], p[0]))
+	n2, n2perm := crossPerm(sub3(q[1], q[0]), sub3(q[2], q[0]))
+	n3, n3perm := crossPerm(sub3(r[1], r[0]), sub3(r[2], r[0]))
+	d1, d1perm := dotPerm(n1, n1perm, sub3(p[0], a))
+	d2, d2perm := dotPerm(n2, n2perm, sub3(q[0], a))
+	d3, d3perm := dotPerm(n3, n3perm, sub3(r[0], a))
+
+	c23, c23perm := crossPerm2(n2, n2perm, n3, n3perm)
+	c31, c31perm := crossPerm2(n3, n3perm, n1, n1perm)
+	c12, c12perm := crossPerm2(n1, n1perm, n2, n2perm)
+
+	w, wperm := dotPerm3(n1, n1perm, c23, c23perm)
+
+	var x, xperm [3]float64
+	for i := 0; i < 3; i++ {
+		x[i] = float64(d1*c23[i]) + float64(d2*c31[i]) + float64(d3*c12[i])
+		xperm[i] = float64(d1perm*c23perm[i]) + float64(d2perm*c31perm[i]) + float64(d3perm*c12perm[i])
+	}
+
+	nabc, nabcperm := crossPerm(sub3(b, a), sub3(c, a))
+	det, permanent := dotPerm3(nabc, nabcperm, x, xperm)
+
+	if math.Abs(w) > tpi3errboundW*wperm {
+		errbound := tpi3errboundA * permanent
+		if (det > errbound) || (-det > errbound) {
+			if w < 0 {
+				return det
+			}
+			return -det
+		}
+	}
+
+	return orient3TPIExact(a, b, c, p, q, r)
+}
+
+// orient3TPIExact is the exact expansion arithmetic fallback for
+// `Orient3TPI`.
+func orient3TPIExact(a, b, c []float64, p, q, r [3][]float64) float64 {
+	n1 := crossExpansion(diffVector(p[1], p[0]), diffVector(p[2], p[0]))
+	n2 := crossExpansion(diffVector(q[1], q[0]), diffVector(q[2], q[0]))
+	n3 := crossExpansion(diffVector(r[1], r[0]), diffVector(r[2], r[0]))
+
+	c23 := crossExpansion(n2, n3)
+	w := dotExpansion(n1, c23)
+	if signExpansion(w) == 0 {
+		return 0
+	}
+	d1 := dotExpansion(n1, diffVector(p[0], a))
+	d2 := dotExpansion(n2, diffVector(q[0], a))
+	d3 := dotExpansion(n3, diffVector(r[0], a))
+
+	x := scaleVector(c23, d1)
+	x = sumVector(x, scaleVector(crossExpansion(n3, n1), d2))
+	x = sumVector(x, scaleVector(crossExpansion(n1, n2), d3))
+
+	nabc := crossExpansion(diffVector(b, a), diffVector(c, a))
+	det := dotExpansion(nabc, x)
+	return -float64(signExpansion(w)) * det[len(det)-1]
+}
+
+// crossPerm returns the cross product of u and v along with the
+// permanent of each component, i.e. the same products summed in
+// absolute value.
+func crossPerm(u, v []float64) (x, perm [3]float64) {
+	for i := 0; i < 3; i++ {
+		j, k := (i+1)%3, (i+2)%3
+		l, r := float64(u[j]*v[k]), float64(u[k]*v[j])
+		x[i] = l - r
+		perm[i] = math.Abs(l) + math.Abs(r)
+	}
+	return x, perm
+}
+
+// crossPerm2 is crossPerm for operands that carry their own permanents.
+func crossPerm2(u, uperm, v, vperm [3]float64) (x, perm [3]float64) {
+	for i := 0; i < 3; i++ {
+		j, k := (i+1)%3, (i+2)%3
+		x[i] = float64(u[j]*v[k]) - float64(u[k]*v[j])
+		perm[i] = float64(uperm[j]*vperm[k]) + float64(uperm[k]*vperm[j])
+	}
+	return x, perm
+}
+
+// dotPerm returns the dot product of u, carrying the permanent uperm,
+// with the exact-input differences v, along with its permanent.
+func dotPerm(u, uperm [3]float64, v []float64) (x, perm float64) {
+	x = float64(u[0]*v[0]) + float64(u[1]*v[1]) + float64(u[2]*v[2])
+	perm = float64(uperm[0]*math.Abs(v[0])) + float64(uperm[1]*math.Abs(v[1])) + float64(uperm[2]*math.Abs(v[2]))
+	return x, perm
+}
+
+// dotPerm3 is dotPerm for two operands that carry their own permanents.
+func dotPerm3(u, uperm, v, vperm [3]float64) (x, perm float64) {
+	x = float64(u[0]*v[0]) + float64(u[1]*v[1]) + float64(u[2]*v[2])
+	perm = float64(uperm[0]*vperm[0]) + float64(uperm[1]*vperm[1]) + float64(uperm[2]*vperm[2])
+	return x, perm
+}
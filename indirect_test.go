@@ -0,0 +1,124 @@
+package robust_test
+
+import (
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+func Test_Orient2LPI(t *testing.T) {
+	fixtures := loadCases(t, "orient2lpi.txt", 12)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			pts := points(tt.args, 2)
+			res := robust.Orient2LPI(pts[0], pts[1],
+				[2][]float64{pts[2], pts[3]},
+				[2][]float64{pts[4], pts[5]},
+			)
+			assert(t, tt.sign, res)
+		})
+	}
+}
+
+func Test_Orient3LPI(t *testing.T) {
+	fixtures := loadCases(t, "orient3lpi.txt", 24)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			pts := points(tt.args, 3)
+			res := robust.Orient3LPI(pts[0], pts[1], pts[2],
+				[2][]float64{pts[3], pts[4]},
+				[3][]float64{pts[5], pts[6], pts[7]},
+			)
+			assert(t, tt.sign, res)
+		})
+	}
+}
+
+func Test_Orient3TPI(t *testing.T) {
+	fixtures := loadCases(t, "orient3tpi.txt", 36)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			pts := points(tt.args, 3)
+			res := robust.Orient3TPI(pts[0], pts[1], pts[2],
+				[3][]float64{pts[3], pts[4], pts[5]},
+				[3][]float64{pts[6], pts[7], pts[8]},
+				[3][]float64{pts[9], pts[10], pts[11]},
+			)
+			assert(t, tt.sign, res)
+		})
+	}
+}
+
+func Test_ComparePointLPI2(t *testing.T) {
+	fixtures := loadCases(t, "comparepointlpi2.txt", 11)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			axis, pts := int(tt.args[0]), points(tt.args[1:], 2)
+			res := robust.ComparePointLPI2(axis, pts[0],
+				[2][]float64{pts[1], pts[2]},
+				[2][]float64{pts[3], pts[4]},
+			)
+			assert(t, tt.sign, res)
+		})
+	}
+}
+
+func Test_CompareLPI2(t *testing.T) {
+	fixtures := loadCases(t, "comparelpi2.txt", 17)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			axis, pts := int(tt.args[0]), points(tt.args[1:], 2)
+			p := [2][]float64{pts[0], pts[1]}
+			q := [2][]float64{pts[2], pts[3]}
+			r := [2][]float64{pts[4], pts[5]}
+			s := [2][]float64{pts[6], pts[7]}
+			assert(t, tt.sign, robust.CompareLPI2(axis, p, q, r, s))
+
+			// Swapping the points flips the sign, swapping the lines of
+			// either point doesn't change it.
+			assert(t, -tt.sign, robust.CompareLPI2(axis, r, s, p, q))
+			assert(t, tt.sign, robust.CompareLPI2(axis, q, p, s, r))
+		})
+	}
+}
+
+func Benchmark_Orient2LPI(b *testing.B) {
+	fixtures := loadCases(b, "orient2lpi.txt", 12)
+	tests := make([][][]float64, len(fixtures))
+	for i, tt := range fixtures {
+		tests[i] = points(tt.args, 2)
+	}
+
+	b.ResetTimer()
+	var res float64
+	for n := 0; n < b.N; n++ {
+		for _, pts := range tests {
+			res = robust.Orient2LPI(pts[0], pts[1],
+				[2][]float64{pts[2], pts[3]},
+				[2][]float64{pts[4], pts[5]},
+			)
+		}
+	}
+	result = res
+}
+
+func Benchmark_Orient3TPI(b *testing.B) {
+	fixtures := loadCases(b, "orient3tpi.txt", 36)
+	tests := make([][][]float64, len(fixtures))
+	for i, tt := range fixtures {
+		tests[i] = points(tt.args, 3)
+	}
+
+	b.ResetTimer()
+	var res float64
+	for n := 0; n < b.N; n++ {
+		for _, pts := range tests {
+			res = robust.Orient3TPI(pts[0], pts[1], pts[2],
+				[3][]float64{pts[3], pts[4], pts[5]},
+				[3][]float64{pts[6], pts[7], pts[8]},
+				[3][]float64{pts[9], pts[10], pts[11]},
+			)
+		}
+	}
+	result = res
+}
@@ -3,7 +3,6 @@ package robust
 // double insphere(double *pa, double *pb, double *pc, double *pd, double *pe);
 // double insphereadapt(double *pa, double *pb, double *pc, double *pd, double *pe, double permanent);
 import "C"
-import "math"
 
 // InSphere returns a positive value if the point e lies inside the
 // sphere passing through a, b, c, and d; a negative value if it lies
@@ -42,6 +41,33 @@ func InSphereVec(a, b, c, d, e *XYZ) float64 {
 	)
 }
 
+// InSphereAt is similar to `InSphere` but takes the points at indices i,
+// j, k, l, and m of a buffer of interleaved points, where point i starts
+// at buf[i*stride]. The bounds of all five points are checked once up
+// front. The stride must be at least 3.
+func InSphereAt(buf []float64, stride, i, j, k, l, m int) float64 {
+	if n := len(buf); stride < 3 ||
+		!inAt(n, stride, 3, i) ||
+		!inAt(n, stride, 3, j) ||
+		!inAt(n, stride, 3, k) ||
+		!inAt(n, stride, 3, l) ||
+		!inAt(n, stride, 3, m) {
+		panicAt(buf, stride, 3, i, j, k, l, m)
+	}
+	i, j, k, l, m = i*stride, j*stride, k*stride, l*stride, m*stride
+	pa := (*C.double)(&buf[i])
+	pb := (*C.double)(&buf[j])
+	pc := (*C.double)(&buf[k])
+	pd := (*C.double)(&buf[l])
+	pe := (*C.double)(&buf[m])
+
+	return inSphere(pa, pb, pc, pd, pe,
+		buf[i]-buf[m], buf[j]-buf[m], buf[k]-buf[m], buf[l]-buf[m],
+		buf[i+1]-buf[m+1], buf[j+1]-buf[m+1], buf[k+1]-buf[m+1], buf[l+1]-buf[m+1],
+		buf[i+2]-buf[m+2], buf[j+2]-buf[m+2], buf[k+2]-buf[m+2], buf[l+2]-buf[m+2],
+	)
+}
+
 // InSpherePtr is the direct wrapper of `insphere` from `predicates.c`.
 // See `InSphere` for additional details.
 func InSpherePtr(a, b, c, d, e *float64) float64 {
@@ -60,76 +86,9 @@ func inSphere(pa, pb, pc, pd, pe *C.double,
 	aey, bey, cey, dey float64,
 	aez, bez, cez, dez float64,
 ) float64 {
-
-	aexbey := aex * bey
-	bexaey := bex * aey
-	ab := aexbey - bexaey
-	bexcey := bex * cey
-	cexbey := cex * bey
-	bc := bexcey - cexbey
-	cexdey := cex * dey
-	dexcey := dex * cey
-	cd := cexdey - dexcey
-	dexaey := dex * aey
-	aexdey := aex * dey
-	da := dexaey - aexdey
-
-	aexcey := aex * cey
-	cexaey := cex * aey
-	ac := aexcey - cexaey
-	bexdey := bex * dey
-	dexbey := dex * bey
-	bd := bexdey - dexbey
-
-	abc := aez*bc - bez*ac + cez*ab
-	bcd := bez*cd - cez*bd + dez*bc
-	cda := cez*da + dez*ac + aez*cd
-	dab := dez*ab + aez*bd + bez*da
-
-	alift := aex*aex + aey*aey + aez*aez
-	blift := bex*bex + bey*bey + bez*bez
-	clift := cex*cex + cey*cey + cez*cez
-	dlift := dex*dex + dey*dey + dez*dez
-
-	det := (dlift*abc - clift*dab) + (blift*cda - alift*bcd)
-
-	aezplus := math.Abs(aez)
-	bezplus := math.Abs(bez)
-	cezplus := math.Abs(cez)
-	dezplus := math.Abs(dez)
-	aexbeyplus := math.Abs(aexbey)
-	bexaeyplus := math.Abs(bexaey)
-	bexceyplus := math.Abs(bexcey)
-	cexbeyplus := math.Abs(cexbey)
-	cexdeyplus := math.Abs(cexdey)
-	dexceyplus := math.Abs(dexcey)
-	dexaeyplus := math.Abs(dexaey)
-	aexdeyplus := math.Abs(aexdey)
-	aexceyplus := math.Abs(aexcey)
-	cexaeyplus := math.Abs(cexaey)
-	bexdeyplus := math.Abs(bexdey)
-	dexbeyplus := math.Abs(dexbey)
-	permanent :=
-		((cexdeyplus+dexceyplus)*bezplus+
-			(dexbeyplus+bexdeyplus)*cezplus+
-			(bexceyplus+cexbeyplus)*dezplus)*
-			alift +
-			((dexaeyplus+aexdeyplus)*cezplus+
-				(aexceyplus+cexaeyplus)*dezplus+
-				(cexdeyplus+dexceyplus)*aezplus)*
-				blift +
-			((aexbeyplus+bexaeyplus)*dezplus+
-				(bexdeyplus+dexbeyplus)*aezplus+
-				(dexaeyplus+aexdeyplus)*bezplus)*
-				clift +
-			((bexceyplus+cexbeyplus)*aezplus+
-				(cexaeyplus+aexceyplus)*bezplus+
-				(aexbeyplus+bexaeyplus)*cezplus)*
-				dlift
-	errbound := isperrboundA * permanent
-	if (det > errbound) || (-det > errbound) {
+	det, permanent, ok := inSphereA(aex, bex, cex, dex, aey, bey, cey, dey, aez, bez, cez, dez)
+	if ok {
 		return det
 	}
-
 	return float64(C.insphereadapt(pa, pb, pc, pd, pe, C.double(permanent)))
 }
@@ -28,6 +28,9 @@ func Test_InSphere(t *testing.T) {
 
 			res = robust.InSphereVec((*robust.XYZ)(&va), (*robust.XYZ)(&vb), (*robust.XYZ)(&vc), (*robust.XYZ)(&vd), (*robust.XYZ)(&ve))
 			assert(t, tt.sign, res)
+
+			res = robust.InSphereAt(interleave(4, a, b, c, d, e), 4, 4, 3, 2, 1, 0)
+			assert(t, tt.sign, res)
 		})
 	}
 }
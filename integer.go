@@ -0,0 +1,351 @@
+package robust
+
+// Integer predicates evaluate the same determinants as their float64
+// counterparts directly in integer arithmetic. Coordinates small enough
+// for every intermediate to fit in an int64 take a fast path, and the
+// rest use the wide two's complement integers below, which hold every
+// intermediate for any int64 input. No filter or cgo call is involved.
+//
+// The fast path bounds follow from the differences, which double the
+// coordinate range, the degree of each determinant and its terms:
+//
+//	Orient2Int   |x| < 2^30   degree 2
+//	Orient3Int   |x| < 2^19   degree 3
+//	InCircleInt  |x| < 2^13   degree 4
+//	InSphereInt  |x| < 2^9    degree 5
+
+const (
+	orient2IntFast  = 1 << 30
+	orient3IntFast  = 1 << 19
+	incircleIntFast = 1 << 13
+	insphereIntFast = 1 << 9
+)
+
+// Orient2Int is the integer version of `Orient2`. It returns 1 if the
+// points a, b, and c occur in counterclockwise order; -1 if they occur in
+// clockwise order; and 0 if they are collinear. The result is exact for
+// all int64 coordinates.
+//
+// Each slice parameter must contain at least 2 values.
+func Orient2Int(a, b, c []int64) int {
+	if within(orient2IntFast, a[:2], b[:2], c[:2]) {
+		det := (a[0]-c[0])*(b[1]-c[1]) - (a[1]-c[1])*(b[0]-c[0])
+		return signInt(det)
+	}
+	acx, acy := diffWide(a[0], c[0]), diffWide(a[1], c[1])
+	bcx, bcy := diffWide(b[0], c[0]), diffWide(b[1], c[1])
+	return acx.mul(bcy).sub(acy.mul(bcx)).sign()
+}
+
+// Orient3Int is the integer version of `Orient3`. It returns 1 if the
+// point d lies below the plane passing through a, b, and c, where below
+// means the points appear counterclockwise when viewed from above; -1 if
+// it lies above; and 0 if the points are coplanar. The result is exact
+// for all int64 coordinates.
+//
+// Each slice parameter must contain at least 3 values.
+func Orient3Int(a, b, c, d []int64) int {
+	if within(orient3IntFast, a[:3], b[:3], c[:3], d[:3]) {
+		adx, ady, adz := a[0]-d[0], a[1]-d[1], a[2]-d[2]
+		bdx, bdy, bdz := b[0]-d[0], b[1]-d[1], b[2]-d[2]
+		cdx, cdy, cdz := c[0]-d[0], c[1]-d[1], c[2]-d[2]
+		det := adx*(bdy*cdz-bdz*cdy) + bdx*(cdy*adz-cdz*ady) + cdx*(ady*bdz-adz*bdy)
+		return signInt(det)
+	}
+	adx, ady, adz := diffWide(a[0], d[0]), diffWide(a[1], d[1]), diffWide(a[2], d[2])
+	bdx, bdy, bdz := diffWide(b[0], d[0]), diffWide(b[1], d[1]), diffWide(b[2], d[2])
+	cdx, cdy, cdz := diffWide(c[0], d[0]), diffWide(c[1], d[1]), diffWide(c[2], d[2])
+	det := adx.mul(bdy.mul(cdz).sub(bdz.mul(cdy)))
+	det = det.add(bdx.mul(cdy.mul(adz).sub(cdz.mul(ady))))
+	det = det.add(cdx.mul(ady.mul(bdz).sub(adz.mul(bdy))))
+	return det.sign()
+}
+
+// InCircleInt is the integer version of `InCircle`. It returns 1 if the
+// point d lies inside the circle passing through a, b, and c; -1 if it
+// lies outside; and 0 if the four points are cocircular. The points a,
+// b, and c must be in counterclockwise order, or the sign of the result
+// will be reversed. The result is exact for all int64 coordinates.
+//
+// Each slice parameter must contain at least 2 values.
+func InCircleInt(a, b, c, d []int64) int {
+	if within(incircleIntFast, a[:2], b[:2], c[:2], d[:2]) {
+		adx, ady := a[0]-d[0], a[1]-d[1]
+		bdx, bdy := b[0]-d[0], b[1]-d[1]
+		cdx, cdy := c[0]-d[0], c[1]-d[1]
+		alift := adx*adx + ady*ady
+		blift := bdx*bdx + bdy*bdy
+		clift := cdx*cdx + cdy*cdy
+		det := alift*(bdx*cdy-cdx*bdy) + blift*(cdx*ady-adx*cdy) + clift*(adx*bdy-bdx*ady)
+		return signInt(det)
+	}
+	adx, ady := diffWide(a[0], d[0]), diffWide(a[1], d[1])
+	bdx, bdy := diffWide(b[0], d[0]), diffWide(b[1], d[1])
+	cdx, cdy := diffWide(c[0], d[0]), diffWide(c[1], d[1])
+	alift := adx.mul(adx).add(ady.mul(ady))
+	blift := bdx.mul(bdx).add(bdy.mul(bdy))
+	clift := cdx.mul(cdx).add(cdy.mul(cdy))
+	det := alift.mul(bdx.mul(cdy).sub(cdx.mul(bdy)))
+	det = det.add(blift.mul(cdx.mul(ady).sub(adx.mul(cdy))))
+	det = det.add(clift.mul(adx.mul(bdy).sub(bdx.mul(ady))))
+	return det.sign()
+}
+
+// InSphereInt is the integer version of `InSphere`. It returns 1 if the
+// point e lies inside the sphere passing through a, b, c, and d; -1 if it
+// lies outside; and 0 if the five points are cospherical. The points a,
+// b, c, and d must be ordered so that they have a positive orientation
+// (as defined by `Orient3`), or the sign of the result will be reversed.
+// The result is exact for all int64 coordinates.
+//
+// Each slice parameter must contain at least 3 values.
+func InSphereInt(a, b, c, d, e []int64) int {
+	if within(insphereIntFast, a[:3], b[:3], c[:3], d[:3], e[:3]) {
+		aex, aey, aez := a[0]-e[0], a[1]-e[1], a[2]-e[2]
+		bex, bey, bez := b[0]-e[0], b[1]-e[1], b[2]-e[2]
+		cex, cey, cez := c[0]-e[0], c[1]-e[1], c[2]-e[2]
+		dex, dey, dez := d[0]-e[0], d[1]-e[1], d[2]-e[2]
+
+		ab := aex*bey - bex*aey
+		bc := bex*cey - cex*bey
+		cd := cex*dey - dex*cey
+		da := dex*aey - aex*dey
+		ac := aex*cey - cex*aey
+		bd := bex*dey - dex*bey
+
+		abc := aez*bc - bez*ac + cez*ab
+		bcd := bez*cd - cez*bd + dez*bc
+		cda := cez*da + dez*ac + aez*cd
+		dab := dez*ab + aez*bd + bez*da
+
+		alift := aex*aex + aey*aey + aez*aez
+		blift := bex*bex + bey*bey + bez*bez
+		clift := cex*cex + cey*cey + cez*cez
+		dlift := dex*dex + dey*dey + dez*dez
+
+		det := (dlift*abc - clift*dab) + (blift*cda - alift*bcd)
+		return signInt(det)
+	}
+	aex, aey, aez := diffWide(a[0], e[0]), diffWide(a[1], e[1]), diffWide(a[2], e[2])
+	bex, bey, bez := diffWide(b[0], e[0]), diffWide(b[1], e[1]), diffWide(b[2], e[2])
+	cex, cey, cez := diffWide(c[0], e[0]), diffWide(c[1], e[1]), diffWide(c[2], e[2])
+	dex, dey, dez := diffWide(d[0], e[0]), diffWide(d[1], e[1]), diffWide(d[2], e[2])
+
+	ab := aex.mul(bey).sub(bex.mul(aey))
+	bc := bex.mul(cey).sub(cex.mul(bey))
+	cd := cex.mul(dey).sub(dex.mul(cey))
+	da := dex.mul(aey).sub(aex.mul(dey))
+	ac := aex.mul(cey).sub(cex.mul(aey))
+	bd := bex.mul(dey).sub(dex.mul(bey))
+
+	abc := aez.mul(bc).sub(bez.mul(ac)).add(cez.mul(ab))
+	bcd := bez.mul(cd).sub(cez.mul(bd)).add(dez.mul(bc))
+	cda := cez.mul(da).add(dez.mul(ac)).add(aez.mul(cd))
+	dab := dez.mul(ab).add(aez.mul(bd)).add(bez.mul(da))
+
+	alift := aex.mul(aex).add(aey.mul(aey)).add(aez.mul(aez))
+	blift := bex.mul(bex).add(bey.mul(bey)).add(bez.mul(bez))
+	clift := cex.mul(cex).add(cey.mul(cey)).add(cez.mul(cez))
+	dlift := dex.mul(dex).add(dey.mul(dey)).add(dez.mul(dez))
+
+	det := dlift.mul(abc).sub(clift.mul(dab)).add(blift.mul(cda).sub(alift.mul(bcd)))
+	return det.sign()
+}
+
+// Orient2Int32 is similar to `Orient2Int` but takes `[]int32` points,
+// which are widened to int64 without copying whole buffers.
+//
+// Each slice parameter must contain at least 2 values.
+func Orient2Int32(a, b, c []int32) int {
+	var p [3][2]int64
+	widen2(p[:], a, b, c)
+	return Orient2Int(p[0][:], p[1][:], p[2][:])
+}
+
+// Orient3Int32 is similar to `Orient3Int` but takes `[]int32` points.
+//
+// Each slice parameter must contain at least 3 values.
+func Orient3Int32(a, b, c, d []int32) int {
+	var p [4][3]int64
+	widen3(p[:], a, b, c, d)
+	return Orient3Int(p[0][:], p[1][:], p[2][:], p[3][:])
+}
+
+// InCircleInt32 is similar to `InCircleInt` but takes `[]int32` points.
+//
+// Each slice parameter must contain at least 2 values.
+func InCircleInt32(a, b, c, d []int32) int {
+	var p [4][2]int64
+	widen2(p[:], a, b, c, d)
+	return InCircleInt(p[0][:], p[1][:], p[2][:], p[3][:])
+}
+
+// InSphereInt32 is similar to `InSphereInt` but takes `[]int32` points.
+//
+// Each slice parameter must contain at least 3 values.
+func InSphereInt32(a, b, c, d, e []int32) int {
+	var p [5][3]int64
+	widen3(p[:], a, b, c, d, e)
+	return InSphereInt(p[0][:], p[1][:], p[2][:], p[3][:], p[4][:])
+}
+
+func widen2(dst [][2]int64, pts ...[]int32) {
+	for i, p := range pts {
+		dst[i] = [2]int64{int64(p[0]), int64(p[1])}
+	}
+}
+
+func widen3(dst [][3]int64, pts ...[]int32) {
+	for i, p := range pts {
+		dst[i] = [3]int64{int64(p[0]), int64(p[1]), int64(p[2])}
+	}
+}
+
+// within reports whether every coordinate lies strictly between -bound
+// and bound.
+func within(bound int64, pts ...[]int64) bool {
+	for _, p := range pts {
+		for _, x := range p {
+			if x <= -bound || x >= bound {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func signInt(x int64) int {
+	if x > 0 {
+		return 1
+	}
+	if x < 0 {
+		return -1
+	}
+	return 0
+}
+
+// wide is a 384-bit two's complement integer with the least significant
+// word first. The largest intermediates, the lifted products of
+// `InSphereInt`, need 333 bits including the sign for int64 inputs, so
+// arithmetic modulo 2^384 never wraps.
+type wide [6]uint64
+
+// diffWide returns a - b.
+func diffWide(a, b int64) wide {
+	return wideInt(a).sub(wideInt(b))
+}
+
+func wideInt(x int64) wide {
+	var ext uint64
+	if x < 0 {
+		ext = ^uint64(0)
+	}
+	return wide{uint64(x), ext, ext, ext, ext, ext}
+}
+
+func (x wide) add(y wide) wide {
+	var z wide
+	var carry uint64
+	for i := range z {
+		s := x[i] + y[i]
+		c := 0
+		if s < x[i] {
+			c = 1
+		}
+		z[i] = s + carry
+		if z[i] < s {
+			c = 1
+		}
+		carry = uint64(c)
+	}
+	return z
+}
+
+func (x wide) sub(y wide) wide {
+	return x.add(y.neg())
+}
+
+func (x wide) neg() wide {
+	for i := range x {
+		x[i] = ^x[i]
+	}
+	return x.add(wide{1})
+}
+
+func (x wide) negative() bool {
+	return int64(x[len(x)-1]) < 0
+}
+
+// mul returns x·y modulo 2^384. It multiplies the magnitudes, skipping
+// their leading zero words, which keeps the products of small
+// intermediates cheap.
+func (x wide) mul(y wide) wide {
+	neg := x.negative() != y.negative()
+	if x.negative() {
+		x = x.neg()
+	}
+	if y.negative() {
+		y = y.neg()
+	}
+	nx, ny := x.words(), y.words()
+
+	var z wide
+	for i := 0; i < nx; i++ {
+		var carry uint64
+		for j := 0; j < ny && i+j < len(z); j++ {
+			hi, lo := mul64(x[i], y[j])
+			lo += carry
+			if lo < carry {
+				hi++
+			}
+			z[i+j] += lo
+			if z[i+j] < lo {
+				hi++
+			}
+			carry = hi
+		}
+		if k := i + ny; k < len(z) {
+			z[k] = carry
+		}
+	}
+	if neg {
+		return z.neg()
+	}
+	return z
+}
+
+// words returns the number of words up to the most significant non-zero
+// one.
+func (x wide) words() int {
+	n := len(x)
+	for n > 0 && x[n-1] == 0 {
+		n--
+	}
+	return n
+}
+
+func (x wide) sign() int {
+	if x.negative() {
+		return -1
+	}
+	if x.words() == 0 {
+		return 0
+	}
+	return 1
+}
+
+// mul64 returns the 128-bit product of x and y. It's `bits.Mul64`, which
+// requires go1.12.
+func mul64(x, y uint64) (hi, lo uint64) {
+	const mask32 = 1<<32 - 1
+	x0, x1 := x&mask32, x>>32
+	y0, y1 := y&mask32, y>>32
+	w0 := x0 * y0
+	t := x1*y0 + w0>>32
+	w1 := t & mask32
+	w2 := t >> 32
+	w1 += x0 * y1
+	hi = x1*y1 + w2 + w1>>32
+	lo = x * y
+	return hi, lo
+}
@@ -0,0 +1,230 @@
+package robust_test
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// intCases generates n points of dim coordinates below bound in magnitude.
+// Every other case is degenerate, with the last point an affine
+// combination of the others, optionally nudged by one unit so that the
+// wide path sees results next to zero.
+func intCases(rng *rand.Rand, n, dim int, bound int64) [][]int64 {
+	pts := make([][]int64, n)
+	for i := range pts {
+		pts[i] = make([]int64, dim)
+		for k := range pts[i] {
+			if bound == math.MaxInt64 {
+				pts[i][k] = int64(rng.Uint64())
+			} else {
+				pts[i][k] = rng.Int63n(2*bound-1) - bound + 1
+			}
+		}
+	}
+	if rng.Intn(2) == 0 {
+		// Collinear or coplanar: step from the first point by small
+		// multiples of short directions to stay in range.
+		dirs := make([][]int64, n-1)
+		for i := range dirs {
+			dirs[i] = make([]int64, dim)
+			for k := range dirs[i] {
+				dirs[i][k] = rng.Int63n(7) - 3
+			}
+		}
+		for i := 1; i < n; i++ {
+			for k := 0; k < dim; k++ {
+				pts[i][k] = pts[0][k]
+				for j := 0; j < dim-1 && j < n-1; j++ {
+					pts[i][k] += int64(i*(j+1)) * dirs[j][k]
+				}
+			}
+		}
+		pts[n-1][0] += rng.Int63n(3) - 1
+	}
+	return pts
+}
+
+// bigDet returns the determinant of the matrix of rows p[i] - q, each
+// followed by its squared length when lift is set.
+func bigDet(q []int64, lift bool, p ...[]int64) int {
+	m := make([][]*big.Int, len(p))
+	for i, pi := range p {
+		sq := new(big.Int)
+		for k := range q {
+			d := new(big.Int).Sub(big.NewInt(pi[k]), big.NewInt(q[k]))
+			m[i] = append(m[i], d)
+			sq.Add(sq, new(big.Int).Mul(d, d))
+		}
+		if lift {
+			m[i] = append(m[i], sq)
+		}
+	}
+	return det(m).Sign()
+}
+
+// det expands along the first row.
+func det(m [][]*big.Int) *big.Int {
+	if len(m) == 1 {
+		return m[0][0]
+	}
+	sum := new(big.Int)
+	for j := range m {
+		var minor [][]*big.Int
+		for _, row := range m[1:] {
+			r := append(append([]*big.Int(nil), row[:j]...), row[j+1:]...)
+			minor = append(minor, r)
+		}
+		t := new(big.Int).Mul(m[0][j], det(minor))
+		if j%2 == 1 {
+			t.Neg(t)
+		}
+		sum.Add(sum, t)
+	}
+	return sum
+}
+
+func floats(p []int64) []float64 {
+	f := make([]float64, len(p))
+	for i, x := range p {
+		f[i] = float64(x)
+	}
+	return f
+}
+
+func Test_IntPredicates(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	// The bounds exercise the fast path, the wide path with exact float64
+	// conversions, and the full int64 range.
+	for _, bound := range []int64{1 << 8, 1 << 40, math.MaxInt64} {
+		for iter := 0; iter < 2000; iter++ {
+			p := intCases(rng, 3, 2, bound)
+			if got, want := robust.Orient2Int(p[0], p[1], p[2]), bigDet(p[2], false, p[0], p[1]); got != want {
+				t.Fatalf("Orient2Int%v: want %d; got %d", p, want, got)
+			}
+			p = intCases(rng, 4, 3, bound)
+			if got, want := robust.Orient3Int(p[0], p[1], p[2], p[3]), bigDet(p[3], false, p[0], p[1], p[2]); got != want {
+				t.Fatalf("Orient3Int%v: want %d; got %d", p, want, got)
+			}
+			p = intCases(rng, 4, 2, bound)
+			if got, want := robust.InCircleInt(p[0], p[1], p[2], p[3]), bigDet(p[3], true, p[0], p[1], p[2]); got != want {
+				t.Fatalf("InCircleInt%v: want %d; got %d", p, want, got)
+			}
+			p = intCases(rng, 5, 3, bound)
+			if got, want := robust.InSphereInt(p[0], p[1], p[2], p[3], p[4]), bigDet(p[4], true, p[0], p[1], p[2], p[3]); got != want {
+				t.Fatalf("InSphereInt%v: want %d; got %d", p, want, got)
+			}
+		}
+	}
+}
+
+func Test_IntPredicatesMatchFloat(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	// Up to 2^53 the conversions are exact, so the signs must agree.
+	for _, bound := range []int64{1 << 8, 1 << 20, 1 << 53} {
+		for iter := 0; iter < 2000; iter++ {
+			p := intCases(rng, 3, 2, bound)
+			assert(t, robust.Orient2Int(p[0], p[1], p[2]), robust.Orient2(floats(p[0]), floats(p[1]), floats(p[2])))
+			p = intCases(rng, 4, 3, bound)
+			assert(t, robust.Orient3Int(p[0], p[1], p[2], p[3]), robust.Orient3(floats(p[0]), floats(p[1]), floats(p[2]), floats(p[3])))
+			p = intCases(rng, 4, 2, bound)
+			assert(t, robust.InCircleInt(p[0], p[1], p[2], p[3]), robust.InCircle(floats(p[0]), floats(p[1]), floats(p[2]), floats(p[3])))
+			p = intCases(rng, 5, 3, bound)
+			assert(t, robust.InSphereInt(p[0], p[1], p[2], p[3], p[4]), robust.InSphere(floats(p[0]), floats(p[1]), floats(p[2]), floats(p[3]), floats(p[4])))
+		}
+	}
+}
+
+func Test_IntPredicatesExtremes(t *testing.T) {
+	const max, min = math.MaxInt64, math.MinInt64
+	// Collinear along the diagonal of the full range
+	if got := robust.Orient2Int([]int64{min + 1, min + 1}, []int64{0, 0}, []int64{max, max}); got != 0 {
+		t.Errorf("diagonal: want 0; got %d", got)
+	}
+	if got := robust.Orient2Int([]int64{min, min + 1}, []int64{0, 0}, []int64{max, max}); got != 1 {
+		t.Errorf("off diagonal: want 1; got %d", got)
+	}
+	if got := robust.InCircleInt([]int64{max, 0}, []int64{0, max}, []int64{-max, 0}, []int64{0, -max}); got != 0 {
+		t.Errorf("cocircular: want 0; got %d", got)
+	}
+	if got := robust.InCircleInt([]int64{max, 0}, []int64{0, max}, []int64{-max, 0}, []int64{0, min}); got != -1 {
+		t.Errorf("outside: want -1; got %d", got)
+	}
+	if got := robust.InSphereInt(
+		[]int64{max, 0, 0}, []int64{0, max, 0}, []int64{0, 0, max}, []int64{-max, 0, 0}, []int64{0, 0, -max},
+	); got != 0 {
+		t.Errorf("cospherical: want 0; got %d", got)
+	}
+}
+
+func Benchmark_Orient2Int(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	for _, bound := range []int64{1 << 20, math.MaxInt64} {
+		tests := make([][][]int64, 1000)
+		for i := range tests {
+			tests[i] = intCases(rng, 3, 2, bound)
+		}
+		b.Run(big.NewInt(bound).String(), func(b *testing.B) {
+			var res int
+			for n := 0; n < b.N; n++ {
+				for _, p := range tests {
+					res = robust.Orient2Int(p[0], p[1], p[2])
+				}
+			}
+			result = float64(res)
+		})
+	}
+}
+
+func Benchmark_InSphereInt(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	for _, bound := range []int64{1 << 8, math.MaxInt64} {
+		tests := make([][][]int64, 1000)
+		for i := range tests {
+			tests[i] = intCases(rng, 5, 3, bound)
+		}
+		b.Run(big.NewInt(bound).String(), func(b *testing.B) {
+			var res int
+			for n := 0; n < b.N; n++ {
+				for _, p := range tests {
+					res = robust.InSphereInt(p[0], p[1], p[2], p[3], p[4])
+				}
+			}
+			result = float64(res)
+		})
+	}
+}
+
+func Test_Int32Predicates(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	narrow := func(p []int64) []int32 {
+		q := make([]int32, len(p))
+		for i, x := range p {
+			q[i] = int32(x)
+		}
+		return q
+	}
+	// The full int32 range leaves the fast paths of every predicate.
+	for _, bound := range []int64{1 << 8, math.MaxInt32} {
+		for iter := 0; iter < 2000; iter++ {
+			p := intCases(rng, 3, 2, bound)
+			assert(t, robust.Orient2Int(p[0], p[1], p[2]), float64(robust.Orient2Int32(narrow(p[0]), narrow(p[1]), narrow(p[2]))))
+			p = intCases(rng, 4, 3, bound)
+			assert(t, robust.Orient3Int(p[0], p[1], p[2], p[3]), float64(robust.Orient3Int32(narrow(p[0]), narrow(p[1]), narrow(p[2]), narrow(p[3]))))
+			p = intCases(rng, 4, 2, bound)
+			assert(t, robust.InCircleInt(p[0], p[1], p[2], p[3]), float64(robust.InCircleInt32(narrow(p[0]), narrow(p[1]), narrow(p[2]), narrow(p[3]))))
+			p = intCases(rng, 5, 3, bound)
+			assert(t, robust.InSphereInt(p[0], p[1], p[2], p[3], p[4]), float64(robust.InSphereInt32(narrow(p[0]), narrow(p[1]), narrow(p[2]), narrow(p[3]), narrow(p[4]))))
+		}
+	}
+	const max, min = math.MaxInt32, math.MinInt32
+	if got := robust.Orient2Int32([]int32{min, min}, []int32{0, 0}, []int32{max, max}); got != 0 {
+		t.Errorf("diagonal: want 0; got %d", got)
+	}
+	if got := robust.Orient2Int32([]int32{min, min + 1}, []int32{0, 0}, []int32{max, max}); got != 1 {
+		t.Errorf("off diagonal: want 1; got %d", got)
+	}
+}
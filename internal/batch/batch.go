@@ -0,0 +1,9 @@
+// Package batch holds the vectorized stage A filters of the batch
+// predicates, which can't live in the robust package since Go assembly
+// isn't allowed alongside cgo.
+//
+// Each filter stores the rounded determinant of every element of pts in
+// out and sets the bits of the zeroed mask for the ones whose sign is
+// certain given the error bound. The number of elements must be a
+// multiple of Lanes.
+package batch
@@ -0,0 +1,45 @@
+package batch
+
+import "neilpa.me/cgo-shewchuk-robust/internal/cpu"
+
+// SSE2 is part of amd64, so the 2-lane filters are always available. The
+// 4-lane ones need AVX, both from the CPU and from the OS saving the YMM
+// registers.
+
+// Lanes is the number of elements the selected filters process at once.
+var Lanes = 2
+
+// Orient2 and Orient3 are the selected filters.
+var (
+	Orient2 = Orient2SSE2
+	Orient3 = Orient3SSE2
+)
+
+func init() {
+	if cpu.X86.HasAVX {
+		Lanes = 4
+		Orient2, Orient3 = Orient2AVX, Orient3AVX
+	}
+}
+
+// Orient2SSE2 filters 2 elements of 6 values at a time with the
+// comparison errbound·(|detleft| + |detright|) <= |det|.
+//
+//go:noescape
+func Orient2SSE2(pts, out []float64, mask []uint64, errbound float64)
+
+// Orient2AVX is `Orient2SSE2` with 4 elements at a time.
+//
+//go:noescape
+func Orient2AVX(pts, out []float64, mask []uint64, errbound float64)
+
+// Orient3SSE2 filters 2 elements of 12 values at a time with the
+// comparison errbound·permanent < |det|.
+//
+//go:noescape
+func Orient3SSE2(pts, out []float64, mask []uint64, errbound float64)
+
+// Orient3AVX is `Orient3SSE2` with 4 elements at a time.
+//
+//go:noescape
+func Orient3AVX(pts, out []float64, mask []uint64, errbound float64)
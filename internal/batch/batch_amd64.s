@@ -0,0 +1,370 @@
+#include "textflag.h"
+
+// The batch filters compute the same operations in the same order as
+// the generic filters of the robust package, so the determinants are
+// bit-identical. Elements are loaded a lane group at a time and
+// transposed so that every register holds one coordinate of one point
+// across the lanes. X13 holds the absolute value mask and X14 the error
+// bound, both broadcast to every lane.
+
+// func Orient2SSE2(pts, out []float64, mask []uint64, errbound float64)
+TEXT ·Orient2SSE2(SB), NOSPLIT, $0-80
+	MOVQ pts_base+0(FP), SI
+	MOVQ out_base+24(FP), DI
+	MOVQ out_len+32(FP), DX
+	MOVQ mask_base+48(FP), R8
+	MOVQ $0x7fffffffffffffff, AX
+	MOVQ AX, X13
+	UNPCKLPD X13, X13
+	MOVSD errbound+72(FP), X14
+	UNPCKLPD X14, X14
+	XORQ CX, CX
+	TESTQ DX, DX
+	JZ done
+
+loop:
+	MOVUPD 0(SI), X0
+	MOVUPD 48(SI), X12
+	MOVAPD X0, X1
+	UNPCKLPD X12, X0
+	UNPCKHPD X12, X1
+	MOVUPD 16(SI), X2
+	MOVUPD 64(SI), X12
+	MOVAPD X2, X3
+	UNPCKLPD X12, X2
+	UNPCKHPD X12, X3
+	MOVUPD 32(SI), X4
+	MOVUPD 80(SI), X12
+	MOVAPD X4, X5
+	UNPCKLPD X12, X4
+	UNPCKHPD X12, X5
+	SUBPD X4, X0 // acx
+	SUBPD X5, X1 // acy
+	SUBPD X4, X2 // bcx
+	SUBPD X5, X3 // bcy
+	MULPD X3, X0 // detleft
+	MULPD X2, X1 // detright
+	MOVAPD X0, X6
+	SUBPD X1, X6 // det
+	MOVUPD X6, (DI)
+	ANDPD X13, X0
+	ANDPD X13, X1
+	ADDPD X1, X0
+	MULPD X14, X0
+	ANDPD X13, X6
+	CMPPD X6, X0, $2 // errbound <= |det|
+	MOVMSKPD X0, AX
+	SHLQ CX, AX
+	MOVQ CX, BX
+	SHRQ $6, BX
+	ORQ AX, (R8)(BX*8)
+	ADDQ $96, SI
+	ADDQ $16, DI
+	ADDQ $2, CX
+	CMPQ CX, DX
+	JLT loop
+
+done:
+	RET
+
+// func Orient2AVX(pts, out []float64, mask []uint64, errbound float64)
+TEXT ·Orient2AVX(SB), NOSPLIT, $0-80
+	MOVQ pts_base+0(FP), SI
+	MOVQ out_base+24(FP), DI
+	MOVQ out_len+32(FP), DX
+	MOVQ mask_base+48(FP), R8
+	MOVQ $0x7fffffffffffffff, AX
+	MOVQ AX, X13
+	VMOVDDUP X13, X13
+	VINSERTF128 $1, X13, Y13, Y13
+	VBROADCASTSD errbound+72(FP), Y14
+	XORQ CX, CX
+	TESTQ DX, DX
+	JZ done
+
+loop:
+	VMOVUPD 0(SI), X0
+	VINSERTF128 $1, 96(SI), Y0, Y0
+	VMOVUPD 48(SI), X12
+	VINSERTF128 $1, 144(SI), Y12, Y12
+	VUNPCKHPD Y12, Y0, Y1
+	VUNPCKLPD Y12, Y0, Y0
+	VMOVUPD 16(SI), X2
+	VINSERTF128 $1, 112(SI), Y2, Y2
+	VMOVUPD 64(SI), X12
+	VINSERTF128 $1, 160(SI), Y12, Y12
+	VUNPCKHPD Y12, Y2, Y3
+	VUNPCKLPD Y12, Y2, Y2
+	VMOVUPD 32(SI), X4
+	VINSERTF128 $1, 128(SI), Y4, Y4
+	VMOVUPD 80(SI), X12
+	VINSERTF128 $1, 176(SI), Y12, Y12
+	VUNPCKHPD Y12, Y4, Y5
+	VUNPCKLPD Y12, Y4, Y4
+	VSUBPD Y4, Y0, Y0 // acx
+	VSUBPD Y5, Y1, Y1 // acy
+	VSUBPD Y4, Y2, Y2 // bcx
+	VSUBPD Y5, Y3, Y3 // bcy
+	VMULPD Y3, Y0, Y6 // detleft
+	VMULPD Y2, Y1, Y7 // detright
+	VSUBPD Y7, Y6, Y8 // det
+	VMOVUPD Y8, (DI)
+	VANDPD Y13, Y6, Y6
+	VANDPD Y13, Y7, Y7
+	VADDPD Y7, Y6, Y6
+	VMULPD Y14, Y6, Y6
+	VANDPD Y13, Y8, Y8
+	VCMPPD $2, Y8, Y6, Y6 // errbound <= |det|
+	VMOVMSKPD Y6, AX
+	SHLQ CX, AX
+	MOVQ CX, BX
+	SHRQ $6, BX
+	ORQ AX, (R8)(BX*8)
+	ADDQ $192, SI
+	ADDQ $32, DI
+	ADDQ $4, CX
+	CMPQ CX, DX
+	JLT loop
+
+done:
+	VZEROUPPER
+	RET
+
+// func Orient3SSE2(pts, out []float64, mask []uint64, errbound float64)
+TEXT ·Orient3SSE2(SB), NOSPLIT, $0-80
+	MOVQ pts_base+0(FP), SI
+	MOVQ out_base+24(FP), DI
+	MOVQ out_len+32(FP), DX
+	MOVQ mask_base+48(FP), R8
+	MOVQ $0x7fffffffffffffff, AX
+	MOVQ AX, X13
+	UNPCKLPD X13, X13
+	MOVSD errbound+72(FP), X14
+	UNPCKLPD X14, X14
+	XORQ CX, CX
+	TESTQ DX, DX
+	JZ done
+
+loop:
+	MOVUPD 72(SI), X9
+	MOVUPD 168(SI), X12
+	MOVAPD X9, X10
+	UNPCKLPD X12, X9
+	UNPCKHPD X12, X10
+	MOVSD 88(SI), X11
+	MOVHPD 184(SI), X11
+	MOVUPD 0(SI), X0
+	MOVUPD 96(SI), X12
+	MOVAPD X0, X3
+	UNPCKLPD X12, X0
+	UNPCKHPD X12, X3
+	MOVSD 16(SI), X6
+	MOVHPD 112(SI), X6
+	MOVUPD 24(SI), X1
+	MOVUPD 120(SI), X12
+	MOVAPD X1, X4
+	UNPCKLPD X12, X1
+	UNPCKHPD X12, X4
+	MOVSD 40(SI), X7
+	MOVHPD 136(SI), X7
+	MOVUPD 48(SI), X2
+	MOVUPD 144(SI), X12
+	MOVAPD X2, X5
+	UNPCKLPD X12, X2
+	UNPCKHPD X12, X5
+	MOVSD 64(SI), X8
+	MOVHPD 160(SI), X8
+	SUBPD X9, X0 // adx
+	SUBPD X10, X3 // ady
+	SUBPD X11, X6 // adz
+	SUBPD X9, X1 // bdx
+	SUBPD X10, X4 // bdy
+	SUBPD X11, X7 // bdz
+	SUBPD X9, X2 // cdx
+	SUBPD X10, X5 // cdy
+	SUBPD X11, X8 // cdz
+
+	MOVAPD X1, X9
+	MULPD X5, X9 // bdxcdy
+	MOVAPD X2, X10
+	MULPD X4, X10 // cdxbdy
+	MOVAPD X9, X11
+	SUBPD X10, X11
+	MULPD X6, X11 // det = adz*(bdxcdy-cdxbdy)
+	ANDPD X13, X9
+	ANDPD X13, X10
+	ADDPD X10, X9
+	ANDPD X13, X6
+	MULPD X6, X9
+	MOVAPD X9, X12 // permanent
+
+	MOVAPD X2, X9
+	MULPD X3, X9 // cdxady
+	MOVAPD X0, X10
+	MULPD X5, X10 // adxcdy
+	MOVAPD X9, X6
+	SUBPD X10, X6
+	MULPD X7, X6
+	ADDPD X6, X11 // det += bdz*(cdxady-adxcdy)
+	ANDPD X13, X9
+	ANDPD X13, X10
+	ADDPD X10, X9
+	ANDPD X13, X7
+	MULPD X7, X9
+	ADDPD X9, X12
+
+	MOVAPD X0, X9
+	MULPD X4, X9 // adxbdy
+	MOVAPD X1, X10
+	MULPD X3, X10 // bdxady
+	MOVAPD X9, X6
+	SUBPD X10, X6
+	MULPD X8, X6
+	ADDPD X6, X11 // det += cdz*(adxbdy-bdxady)
+	ANDPD X13, X9
+	ANDPD X13, X10
+	ADDPD X10, X9
+	ANDPD X13, X8
+	MULPD X8, X9
+	ADDPD X9, X12
+
+	MOVUPD X11, (DI)
+	MULPD X14, X12
+	ANDPD X13, X11
+	CMPPD X11, X12, $1 // errbound < |det|
+	MOVMSKPD X12, AX
+	SHLQ CX, AX
+	MOVQ CX, BX
+	SHRQ $6, BX
+	ORQ AX, (R8)(BX*8)
+	ADDQ $192, SI
+	ADDQ $16, DI
+	ADDQ $2, CX
+	CMPQ CX, DX
+	JLT loop
+
+done:
+	RET
+
+// func Orient3AVX(pts, out []float64, mask []uint64, errbound float64)
+TEXT ·Orient3AVX(SB), NOSPLIT, $0-80
+	MOVQ pts_base+0(FP), SI
+	MOVQ out_base+24(FP), DI
+	MOVQ out_len+32(FP), DX
+	MOVQ mask_base+48(FP), R8
+	MOVQ $0x7fffffffffffffff, AX
+	MOVQ AX, X13
+	VMOVDDUP X13, X13
+	VINSERTF128 $1, X13, Y13, Y13
+	VBROADCASTSD errbound+72(FP), Y14
+	XORQ CX, CX
+	TESTQ DX, DX
+	JZ done
+
+loop:
+	VMOVUPD 72(SI), X9
+	VINSERTF128 $1, 264(SI), Y9, Y9
+	VMOVUPD 168(SI), X12
+	VINSERTF128 $1, 360(SI), Y12, Y12
+	VUNPCKHPD Y12, Y9, Y10
+	VUNPCKLPD Y12, Y9, Y9
+	VMOVSD 88(SI), X11
+	VMOVHPD 184(SI), X11, X11
+	VMOVSD 280(SI), X12
+	VMOVHPD 376(SI), X12, X12
+	VINSERTF128 $1, X12, Y11, Y11
+	VMOVUPD 0(SI), X0
+	VINSERTF128 $1, 192(SI), Y0, Y0
+	VMOVUPD 96(SI), X12
+	VINSERTF128 $1, 288(SI), Y12, Y12
+	VUNPCKHPD Y12, Y0, Y3
+	VUNPCKLPD Y12, Y0, Y0
+	VMOVSD 16(SI), X6
+	VMOVHPD 112(SI), X6, X6
+	VMOVSD 208(SI), X12
+	VMOVHPD 304(SI), X12, X12
+	VINSERTF128 $1, X12, Y6, Y6
+	VMOVUPD 24(SI), X1
+	VINSERTF128 $1, 216(SI), Y1, Y1
+	VMOVUPD 120(SI), X12
+	VINSERTF128 $1, 312(SI), Y12, Y12
+	VUNPCKHPD Y12, Y1, Y4
+	VUNPCKLPD Y12, Y1, Y1
+	VMOVSD 40(SI), X7
+	VMOVHPD 136(SI), X7, X7
+	VMOVSD 232(SI), X12
+	VMOVHPD 328(SI), X12, X12
+	VINSERTF128 $1, X12, Y7, Y7
+	VMOVUPD 48(SI), X2
+	VINSERTF128 $1, 240(SI), Y2, Y2
+	VMOVUPD 144(SI), X12
+	VINSERTF128 $1, 336(SI), Y12, Y12
+	VUNPCKHPD Y12, Y2, Y5
+	VUNPCKLPD Y12, Y2, Y2
+	VMOVSD 64(SI), X8
+	VMOVHPD 160(SI), X8, X8
+	VMOVSD 256(SI), X12
+	VMOVHPD 352(SI), X12, X12
+	VINSERTF128 $1, X12, Y8, Y8
+	VSUBPD Y9, Y0, Y0 // adx
+	VSUBPD Y10, Y3, Y3 // ady
+	VSUBPD Y11, Y6, Y6 // adz
+	VSUBPD Y9, Y1, Y1 // bdx
+	VSUBPD Y10, Y4, Y4 // bdy
+	VSUBPD Y11, Y7, Y7 // bdz
+	VSUBPD Y9, Y2, Y2 // cdx
+	VSUBPD Y10, Y5, Y5 // cdy
+	VSUBPD Y11, Y8, Y8 // cdz
+
+	VMULPD Y5, Y1, Y9 // bdxcdy
+	VMULPD Y4, Y2, Y10 // cdxbdy
+	VSUBPD Y10, Y9, Y11
+	VMULPD Y11, Y6, Y11 // det = adz*(bdxcdy-cdxbdy)
+	VANDPD Y13, Y9, Y9
+	VANDPD Y13, Y10, Y10
+	VADDPD Y10, Y9, Y9
+	VANDPD Y13, Y6, Y6
+	VMULPD Y6, Y9, Y12 // permanent
+
+	VMULPD Y3, Y2, Y9 // cdxady
+	VMULPD Y5, Y0, Y10 // adxcdy
+	VSUBPD Y10, Y9, Y6
+	VMULPD Y6, Y7, Y6
+	VADDPD Y6, Y11, Y11 // det += bdz*(cdxady-adxcdy)
+	VANDPD Y13, Y9, Y9
+	VANDPD Y13, Y10, Y10
+	VADDPD Y10, Y9, Y9
+	VANDPD Y13, Y7, Y7
+	VMULPD Y7, Y9, Y9
+	VADDPD Y9, Y12, Y12
+
+	VMULPD Y4, Y0, Y9 // adxbdy
+	VMULPD Y3, Y1, Y10 // bdxady
+	VSUBPD Y10, Y9, Y6
+	VMULPD Y6, Y8, Y6
+	VADDPD Y6, Y11, Y11 // det += cdz*(adxbdy-bdxady)
+	VANDPD Y13, Y9, Y9
+	VANDPD Y13, Y10, Y10
+	VADDPD Y10, Y9, Y9
+	VANDPD Y13, Y8, Y8
+	VMULPD Y8, Y9, Y9
+	VADDPD Y9, Y12, Y12
+
+	VMOVUPD Y11, (DI)
+	VMULPD Y14, Y12, Y12
+	VANDPD Y13, Y11, Y11
+	VCMPPD $1, Y11, Y12, Y12 // errbound < |det|
+	VMOVMSKPD Y12, AX
+	SHLQ CX, AX
+	MOVQ CX, BX
+	SHRQ $6, BX
+	ORQ AX, (R8)(BX*8)
+	ADDQ $384, SI
+	ADDQ $32, DI
+	ADDQ $4, CX
+	CMPQ CX, DX
+	JLT loop
+
+done:
+	VZEROUPPER
+	RET
@@ -0,0 +1,83 @@
+package batch
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"neilpa.me/cgo-shewchuk-robust/internal/cpu"
+)
+
+// reference filters in plain Go, following the operation order of the
+// kernels, with the products rounded like the kernels do even when the
+// compiler could fuse them.
+func orient2Ref(pts, out []float64, mask []uint64, errbound float64) {
+	for i := range out {
+		p := pts[6*i : 6*i+6]
+		detleft := float64((p[0] - p[4]) * (p[3] - p[5]))
+		detright := float64((p[1] - p[5]) * (p[2] - p[4]))
+		out[i] = detleft - detright
+		if errbound*(math.Abs(detleft)+math.Abs(detright)) <= math.Abs(out[i]) {
+			mask[i/64] |= 1 << uint(i%64)
+		}
+	}
+}
+
+func orient3Ref(pts, out []float64, mask []uint64, errbound float64) {
+	for i := range out {
+		p := pts[12*i : 12*i+12]
+		adx, bdx, cdx := p[0]-p[9], p[3]-p[9], p[6]-p[9]
+		ady, bdy, cdy := p[1]-p[10], p[4]-p[10], p[7]-p[10]
+		adz, bdz, cdz := p[2]-p[11], p[5]-p[11], p[8]-p[11]
+		bdxcdy, cdxbdy := float64(bdx*cdy), float64(cdx*bdy)
+		cdxady, adxcdy := float64(cdx*ady), float64(adx*cdy)
+		adxbdy, bdxady := float64(adx*bdy), float64(bdx*ady)
+		out[i] = float64(adz*(bdxcdy-cdxbdy)) + float64(bdz*(cdxady-adxcdy)) + float64(cdz*(adxbdy-bdxady))
+		permanent := float64((math.Abs(bdxcdy)+math.Abs(cdxbdy))*math.Abs(adz)) +
+			float64((math.Abs(cdxady)+math.Abs(adxcdy))*math.Abs(bdz)) +
+			float64((math.Abs(adxbdy)+math.Abs(bdxady))*math.Abs(cdz))
+		if errbound*permanent < math.Abs(out[i]) {
+			mask[i/64] |= 1 << uint(i%64)
+		}
+	}
+}
+
+type filter func(pts, out []float64, mask []uint64, errbound float64)
+
+func check(t *testing.T, label string, stride int, got, want filter) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 4 * 100
+	pts := make([]float64, stride*n)
+	for i := range pts {
+		// Mix scales and exact grid values so both outcomes occur
+		pts[i] = math.Ldexp(rng.Float64(), rng.Intn(8))
+		if rng.Intn(2) == 0 {
+			pts[i] = math.Floor(pts[i])
+		}
+	}
+	const errbound = 1e-3
+	gout, wout := make([]float64, n), make([]float64, n)
+	gmask, wmask := make([]uint64, (n+63)/64), make([]uint64, (n+63)/64)
+	got(pts, gout, gmask, errbound)
+	want(pts, wout, wmask, errbound)
+	for i := range wout {
+		if math.Float64bits(gout[i]) != math.Float64bits(wout[i]) {
+			t.Fatalf("%s element %d: want %g; got %g", label, i, wout[i], gout[i])
+		}
+	}
+	for i := range wmask {
+		if gmask[i] != wmask[i] {
+			t.Fatalf("%s mask word %d: want %#x; got %#x", label, i, wmask[i], gmask[i])
+		}
+	}
+}
+
+func Test_Filters(t *testing.T) {
+	check(t, "Orient2SSE2", 6, Orient2SSE2, orient2Ref)
+	check(t, "Orient3SSE2", 12, Orient3SSE2, orient3Ref)
+	if !cpu.X86.HasAVX {
+		t.Skip("no AVX")
+	}
+	check(t, "Orient2AVX", 6, Orient2AVX, orient2Ref)
+	check(t, "Orient3AVX", 12, Orient3AVX, orient3Ref)
+}
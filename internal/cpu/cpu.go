@@ -0,0 +1,14 @@
+// Package cpu detects the processor features used by the vectorized
+// filters and the fused multiply-add products.
+package cpu
+
+// X86 holds the features of amd64 processors, which are all false on
+// other architectures.
+var X86 struct {
+	// HasAVX reports AVX support by both the CPU and the OS, which must
+	// save the YMM registers.
+	HasAVX bool
+	// HasFMA reports hardware support for fused multiply-add, without
+	// which math.FMA is emulated in software.
+	HasFMA bool
+}
@@ -0,0 +1,18 @@
+package cpu
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+func xgetbv() (eax, edx uint32)
+
+func init() {
+	_, _, ecx, _ := cpuid(1, 0)
+	const fma, osxsave, avx = 1 << 12, 1 << 27, 1 << 28
+	// XMM and YMM state enabled by the OS
+	var ymm bool
+	if ecx&osxsave != 0 {
+		eax, _ := xgetbv()
+		ymm = eax&6 == 6
+	}
+	X86.HasAVX = ecx&avx != 0 && ymm
+	X86.HasFMA = ecx&fma != 0 && X86.HasAVX
+}
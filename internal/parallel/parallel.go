@@ -0,0 +1,34 @@
+// Package parallel splits loops across goroutines.
+package parallel
+
+import (
+	"runtime"
+	"sync"
+)
+
+// For splits [0, n) into contiguous chunks run on GOMAXPROCS goroutines
+// and waits for them to finish.
+func For(n int, fn func(lo, hi int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		fn(0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	chunk := (n + workers - 1) / workers
+	for lo := 0; lo < n; lo += chunk {
+		hi := lo + chunk
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
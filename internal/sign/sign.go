@@ -0,0 +1,13 @@
+// Package sign converts predicate results to signs.
+package sign
+
+// Of returns the sign of a predicate result as an int.
+func Of(v float64) int {
+	if v > 0 {
+		return 1
+	}
+	if v < 0 {
+		return -1
+	}
+	return 0
+}
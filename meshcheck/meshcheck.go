@@ -0,0 +1,436 @@
+// Package meshcheck validates meshes with exact predicates. For triangle
+// meshes it reports self-intersecting face pairs, non-manifold edges,
+// edges with inconsistent orientation and degenerate triangles. For
+// tetrahedral meshes it reports inverted and flat elements along with
+// faces that aren't locally Delaunay.
+//
+// Meshes are given as a flat `[]float64` vertex buffer of XYZ triples and
+// a flat `[]int32` index buffer of vertex triples per face, or quadruples
+// per tetrahedron.
+package meshcheck
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+	"neilpa.me/cgo-shewchuk-robust/internal/sign"
+)
+
+// ErrInvalid is returned for malformed buffers or out of range indices.
+var ErrInvalid = errors.New("meshcheck: invalid mesh")
+
+// Edge is an undirected mesh edge as a pair of vertex indices with the
+// smaller index first.
+type Edge [2]int32
+
+// Report lists the problems found in a mesh. Every list is sorted.
+type Report struct {
+	// Intersections holds the pairs of faces (i < j) that intersect
+	// other than along their shared edge or vertex.
+	Intersections [][2]int
+	// NonManifold holds the edges with more than two incident faces.
+	NonManifold []Edge
+	// Inconsistent holds the edges traversed in the same direction by
+	// two of their faces, i.e. where the orientation flips.
+	Inconsistent []Edge
+	// Degenerate holds the faces with repeated or collinear vertices.
+	// They are excluded from the intersection tests.
+	Degenerate []int
+}
+
+// Valid reports whether no problems were found.
+func (r *Report) Valid() bool {
+	return len(r.Intersections) == 0 && len(r.NonManifold) == 0 &&
+		len(r.Inconsistent) == 0 && len(r.Degenerate) == 0
+}
+
+// Check validates the mesh. Candidate face pairs for intersection are
+// pruned with a uniform grid over the face bounding boxes and then
+// classified exactly with `robust.TriTriIntersect3`.
+func Check(verts []float64, tris []int32) (*Report, error) {
+	if len(verts)%3 != 0 || len(tris)%3 != 0 {
+		return nil, ErrInvalid
+	}
+	nverts := int32(len(verts) / 3)
+	for _, v := range tris {
+		if v < 0 || v >= nverts {
+			return nil, ErrInvalid
+		}
+	}
+
+	m := &mesh{verts: verts, tris: tris}
+	r := &Report{}
+	r.NonManifold, r.Inconsistent = m.edges()
+
+	skip := make([]bool, m.faces())
+	for f := range skip {
+		if m.degenerate(f) {
+			skip[f] = true
+			r.Degenerate = append(r.Degenerate, f)
+		}
+	}
+	r.Intersections = m.intersections(skip)
+	return r, nil
+}
+
+// mesh wraps the raw buffers.
+type mesh struct {
+	verts []float64
+	tris  []int32
+}
+
+func (m *mesh) faces() int {
+	return len(m.tris) / 3
+}
+
+func (m *mesh) vertex(v int32) []float64 {
+	return m.verts[3*v : 3*v+3]
+}
+
+func (m *mesh) face(f int) []int32 {
+	return m.tris[3*f : 3*f+3]
+}
+
+// edges finds the non-manifold and inconsistently oriented edges.
+func (m *mesh) edges() (nonManifold, inconsistent []Edge) {
+	type use struct{ forward, backward int }
+	uses := make(map[Edge]*use)
+	for f := 0; f < m.faces(); f++ {
+		t := m.face(f)
+		for k := 0; k < 3; k++ {
+			a, b := t[k], t[(k+1)%3]
+			if a == b {
+				continue
+			}
+			e, forward := Edge{a, b}, true
+			if a > b {
+				e, forward = Edge{b, a}, false
+			}
+			u := uses[e]
+			if u == nil {
+				u = &use{}
+				uses[e] = u
+			}
+			if forward {
+				u.forward++
+			} else {
+				u.backward++
+			}
+		}
+	}
+	for e, u := range uses {
+		if u.forward+u.backward > 2 {
+			nonManifold = append(nonManifold, e)
+		} else if u.forward == 2 || u.backward == 2 {
+			inconsistent = append(inconsistent, e)
+		}
+	}
+	sortEdges(nonManifold)
+	sortEdges(inconsistent)
+	return nonManifold, inconsistent
+}
+
+// degenerate reports whether the face has repeated or collinear
+// vertices, which holds iff all three coordinate projections are.
+func (m *mesh) degenerate(f int) bool {
+	t := m.face(f)
+	if t[0] == t[1] || t[1] == t[2] || t[2] == t[0] {
+		return true
+	}
+	a, b, c := m.vertex(t[0]), m.vertex(t[1]), m.vertex(t[2])
+	for axis := 0; axis < 3; axis++ {
+		i, j := (axis+1)%3, (axis+2)%3
+		pa := []float64{a[i], a[j]}
+		pb := []float64{b[i], b[j]}
+		pc := []float64{c[i], c[j]}
+		if robust.Orient2(pa, pb, pc) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// intersections tests every candidate pair of non-degenerate faces
+// whose bounding boxes overlap.
+func (m *mesh) intersections(skip []bool) [][2]int {
+	n := m.faces()
+	boxes := make([]box, n)
+	for f := range boxes {
+		t := m.face(f)
+		boxes[f] = boxOf(m.vertex(t[0]), m.vertex(t[1]), m.vertex(t[2]))
+	}
+	g := newGrid(boxes, skip)
+
+	var pairs [][2]int
+	stamp := make([]int, n)
+	for f := 0; f < n; f++ {
+		if skip[f] {
+			continue
+		}
+		g.visit(boxes[f], func(o int) {
+			if o <= f || stamp[o] == f+1 || !boxes[f].overlaps(boxes[o]) {
+				return
+			}
+			stamp[o] = f + 1
+			if m.intersect(f, o) {
+				pairs = append(pairs, [2]int{f, o})
+			}
+		})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	return pairs
+}
+
+// intersect reports whether the faces f and o intersect beyond what
+// their shared vertices allow. Faces sharing an edge always touch there,
+// so only a coplanar overlap or a proper crossing counts. Faces sharing
+// a vertex may also touch elsewhere, e.g. along an edge running from the
+// vertex into the other face, which does count.
+func (m *mesh) intersect(f, o int) bool {
+	tf, to := m.face(f), m.face(o)
+	shared := 0
+	var fv, ov int
+	for i, a := range tf {
+		for j, b := range to {
+			if a == b {
+				shared++
+				fv, ov = i, j
+			}
+		}
+	}
+	res := robust.TriTriIntersect3(
+		m.vertex(tf[0]), m.vertex(tf[1]), m.vertex(tf[2]),
+		m.vertex(to[0]), m.vertex(to[1]), m.vertex(to[2]),
+	)
+	switch {
+	case shared == 0:
+		return res != robust.TriTriDisjoint
+	case shared == 1 && res == robust.TriTriTouching:
+		return touchBeyond(m.vertex(tf[fv]),
+			m.vertex(tf[(fv+1)%3]), m.vertex(tf[(fv+2)%3]),
+			m.vertex(to[(ov+1)%3]), m.vertex(to[(ov+2)%3]),
+		)
+	}
+	return res == robust.TriTriCoplanar || res == robust.TriTriProper
+}
+
+// touchBeyond reports whether the triangles v, a1, a2 and v, b1, b2 have
+// a point in common other than v. Their intersection is convex and
+// contains v, so that's the case exactly when the sectors of the
+// triangles at v share a ray.
+func touchBeyond(v, a1, a2, b1, b2 []float64) bool {
+	s1 := sign.Of(robust.Orient3(v, b1, b2, a1))
+	s2 := sign.Of(robust.Orient3(v, b1, b2, a2))
+	t1 := sign.Of(robust.Orient3(v, a1, a2, b1))
+	t2 := sign.Of(robust.Orient3(v, a1, a2, b2))
+	if s1 == 0 && s2 == 0 {
+		return coplanarTouchBeyond(v, a1, a2, b1, b2)
+	}
+	if s1 == s2 || t1 == t2 && t1 != 0 {
+		return false
+	}
+
+	// The sectors meet the line shared by both planes in a ray each, so
+	// they share one if the ray of the first lies in the second. The ray
+	// is along an edge in the other plane or through the point where a1a2
+	// crosses it, and orientations within that plane are measured
+	// against a witness off it.
+	w := a1
+	if s1 == 0 {
+		w = a2
+	}
+	ws := sign.Of(robust.Orient3(v, b1, b2, w))
+	var orient func(p, q []float64) int
+	switch {
+	case s1 == 0:
+		orient = func(p, q []float64) int { return sign.Of(robust.Orient3(p, q, a1, w)) * ws }
+	case s2 == 0:
+		orient = func(p, q []float64) int { return sign.Of(robust.Orient3(p, q, a2, w)) * ws }
+	default:
+		line, plane := [2][]float64{a1, a2}, [3][]float64{v, b1, b2}
+		orient = func(p, q []float64) int { return -sign.Of(robust.Orient3LPI(p, q, w, line, plane)) * ws }
+	}
+	return rayInSector(v, b1, b2, 1, orient)
+}
+
+// coplanarTouchBeyond is touchBeyond for coplanar triangles, whose
+// sectors share a ray when one of their boundary rays lies in the other.
+// Orientations are measured on the coordinate projection where the
+// triangles don't degenerate.
+func coplanarTouchBeyond(v, a1, a2, b1, b2 []float64) bool {
+	k := 0
+	for ; k < 2; k++ {
+		if robust.Orient2(project(v, k), project(a1, k), project(a2, k)) != 0 {
+			break
+		}
+	}
+	orient := func(p, q, x []float64) int {
+		return sign.Of(robust.Orient2(project(p, k), project(q, k), project(x, k)))
+	}
+	sa, sb := orient(v, a1, a2), orient(v, b1, b2)
+	in := func(p, q []float64, s int, x []float64) bool {
+		return rayInSector(v, p, q, s, func(p, q []float64) int { return orient(p, q, x) })
+	}
+	return in(b1, b2, sb, a1) || in(b1, b2, sb, a2) || in(a1, a2, sa, b1) || in(a1, a2, sa, b2)
+}
+
+// rayInSector reports whether a ray from v lies in the closed sector at
+// v spanned by p and q, where s is the orientation of v, p, q and
+// orient(p, q) is the orientation of p, q and any point on the ray.
+func rayInSector(v, p, q []float64, s int, orient func(p, q []float64) int) bool {
+	s1, s2 := orient(v, p), orient(q, v)
+	return (s1 == 0 || s1 == s) && (s2 == 0 || s2 == s) && (s1 != 0 || s2 != 0)
+}
+
+// project drops coordinate 2-k, giving the xy, zx and yz projections for
+// k of 0, 1 and 2.
+func project(p []float64, k int) []float64 {
+	switch k {
+	case 0:
+		return []float64{p[0], p[1]}
+	case 1:
+		return []float64{p[2], p[0]}
+	}
+	return []float64{p[1], p[2]}
+}
+
+// box is an axis aligned bounding box.
+type box struct {
+	min, max [3]float64
+}
+
+func boxOf(pts ...[]float64) box {
+	b := box{min: [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}, max: [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}}
+	for _, p := range pts {
+		for i := 0; i < 3; i++ {
+			b.min[i] = math.Min(b.min[i], p[i])
+			b.max[i] = math.Max(b.max[i], p[i])
+		}
+	}
+	return b
+}
+
+func (b box) overlaps(o box) bool {
+	for i := 0; i < 3; i++ {
+		if b.max[i] < o.min[i] || o.max[i] < b.min[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// grid is a uniform spatial hash of face bounding boxes. Cells are
+// sized to the average box so that each face lands in a few cells, and
+// the rare faces spanning too many cells are kept aside in a list that
+// every query visits.
+type grid struct {
+	origin [3]float64
+	size   float64
+	cells  map[[3]int][]int
+	big    []int
+	faces  []int
+}
+
+// maxSpan is the most cells a box may cover before it's treated as big.
+const maxSpan = 512
+
+func newGrid(boxes []box, skip []bool) *grid {
+	g := &grid{cells: make(map[[3]int][]int)}
+	var bounds box
+	extent := 0.0
+	for f, b := range boxes {
+		if skip[f] {
+			continue
+		}
+		if len(g.faces) == 0 {
+			bounds = b
+		}
+		bounds = boxOf(bounds.min[:], bounds.max[:], b.min[:], b.max[:])
+		for i := 0; i < 3; i++ {
+			extent += b.max[i] - b.min[i]
+		}
+		g.faces = append(g.faces, f)
+	}
+	if len(g.faces) == 0 {
+		return g
+	}
+	g.origin = bounds.min
+	g.size = extent / float64(3*len(g.faces))
+	if !(g.size > 0) {
+		g.size = 1
+	}
+	for _, f := range g.faces {
+		if g.span(boxes[f]) > maxSpan {
+			g.big = append(g.big, f)
+			continue
+		}
+		g.each(boxes[f], func(c [3]int) { g.cells[c] = append(g.cells[c], f) })
+	}
+	return g
+}
+
+// visit calls fn for every face that may overlap b. A face may be
+// visited more than once.
+func (g *grid) visit(b box, fn func(f int)) {
+	if g.span(b) > maxSpan {
+		for _, f := range g.faces {
+			fn(f)
+		}
+		return
+	}
+	for _, f := range g.big {
+		fn(f)
+	}
+	g.each(b, func(c [3]int) {
+		for _, f := range g.cells[c] {
+			fn(f)
+		}
+	})
+}
+
+// cell returns the range of cell indices covered by b.
+func (g *grid) cell(b box) (lo, hi [3]int) {
+	for i := 0; i < 3; i++ {
+		lo[i] = int(math.Floor((b.min[i] - g.origin[i]) / g.size))
+		hi[i] = int(math.Floor((b.max[i] - g.origin[i]) / g.size))
+	}
+	return lo, hi
+}
+
+// span returns the number of cells covered by b.
+func (g *grid) span(b box) float64 {
+	lo, hi := g.cell(b)
+	n := 1.0
+	for i := 0; i < 3; i++ {
+		n *= float64(hi[i] - lo[i] + 1)
+	}
+	return n
+}
+
+// each calls fn for every cell covered by b.
+func (g *grid) each(b box, fn func(c [3]int)) {
+	lo, hi := g.cell(b)
+	for x := lo[0]; x <= hi[0]; x++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for z := lo[2]; z <= hi[2]; z++ {
+				fn([3]int{x, y, z})
+			}
+		}
+	}
+}
+
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i][0] != edges[j][0] {
+			return edges[i][0] < edges[j][0]
+		}
+		return edges[i][1] < edges[j][1]
+	})
+}
@@ -0,0 +1,129 @@
+package meshcheck_test
+
+import (
+	"reflect"
+	"testing"
+
+	"neilpa.me/cgo-shewchuk-robust/meshcheck"
+)
+
+// tetra is a closed, consistently oriented tetrahedron.
+var (
+	tetraVerts = []float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1}
+	tetraTris  = []int32{0, 2, 1, 0, 1, 3, 1, 2, 3, 0, 3, 2}
+)
+
+func Test_CheckValid(t *testing.T) {
+	r, err := meshcheck.Check(tetraVerts, tetraTris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Valid() {
+		t.Errorf("want: valid; got: %+v", r)
+	}
+}
+
+func Test_CheckIntersections(t *testing.T) {
+	// Second tetrahedron shifted so that it pierces the first
+	verts := append([]float64{}, tetraVerts...)
+	for i := 0; i < len(tetraVerts); i += 3 {
+		verts = append(verts, tetraVerts[i]+0.2, tetraVerts[i+1]+0.2, tetraVerts[i+2]+0.2)
+	}
+	tris := append([]int32{}, tetraTris...)
+	for _, v := range tetraTris {
+		tris = append(tris, v+4)
+	}
+
+	r, err := meshcheck.Check(verts, tris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Intersections) == 0 {
+		t.Fatalf("want: intersections; got: %+v", r)
+	}
+	for _, p := range r.Intersections {
+		if p[0] >= 4 || p[1] < 4 {
+			t.Errorf("unexpected pair within one tetrahedron: %v", p)
+		}
+	}
+	if len(r.NonManifold)+len(r.Inconsistent)+len(r.Degenerate) != 0 {
+		t.Errorf("unexpected problems: %+v", r)
+	}
+}
+
+func Test_CheckTouching(t *testing.T) {
+	// A mirrored tetrahedron sharing only vertex 0, which isn't an
+	// intersection.
+	verts := append([]float64{}, tetraVerts...)
+	verts = append(verts, -1, 0, 0, 0, -1, 0, 0, 0, -1)
+	tris := append([]int32{}, tetraTris...)
+	tris = append(tris, 0, 4, 5, 0, 6, 4, 0, 5, 6, 4, 6, 5)
+
+	r, err := meshcheck.Check(verts, tris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Valid() {
+		t.Errorf("want: valid; got: %+v", r)
+	}
+}
+
+func Test_CheckSharedVertex(t *testing.T) {
+	// Faces 0 1 2 and 0 3 4 share only vertex 0, with the first in the
+	// z=0 plane.
+	for _, tt := range []struct {
+		label     string
+		a1, a2    []float64
+		intersect bool
+	}{
+		{"edge into face", []float64{1, 0.5, 0}, []float64{0, 0, 1}, true},
+		{"collinear edges", []float64{1, 0, 0}, []float64{0, 0, 1}, true},
+		{"edge away", []float64{-1, -0.5, 0}, []float64{0, 0, 1}, false},
+		{"crossing inside", []float64{0.5, 0.5, -1}, []float64{0.5, 0.5, 1}, true},
+		{"crossing outside", []float64{-0.5, -0.5, -1}, []float64{-0.5, -0.5, 1}, false},
+		{"crossing edge ray", []float64{1, 0, -1}, []float64{1, 0, 1}, true},
+		{"crossing opposite ray", []float64{-1, 0, -1}, []float64{-1, 0, 1}, false},
+		{"coplanar opposite", []float64{-1, 0, 0}, []float64{0, -1, 0}, false},
+		{"coplanar collinear edges", []float64{1, 0, 0}, []float64{0, -1, 0}, true},
+	} {
+		t.Run(tt.label, func(t *testing.T) {
+			verts := []float64{0, 0, 0, 2, 0, 0, 0, 2, 0}
+			verts = append(append(verts, tt.a1...), tt.a2...)
+			r, err := meshcheck.Check(verts, []int32{0, 1, 2, 0, 3, 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(r.Intersections) > 0; got != tt.intersect {
+				t.Errorf("want intersect %v; got %v", tt.intersect, r.Intersections)
+			}
+		})
+	}
+}
+
+func Test_CheckTopology(t *testing.T) {
+	verts := append([]float64{}, tetraVerts...)
+	verts = append(verts, 1, 1, 1, 2, 2, 2)
+
+	// Flip one face, add a third face on edge 0-1 and a degenerate face
+	tris := []int32{0, 1, 2, 0, 1, 3, 1, 2, 3, 0, 3, 2, 0, 1, 4, 0, 4, 5}
+
+	r, err := meshcheck.Check(verts, tris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []meshcheck.Edge{{0, 1}}; !reflect.DeepEqual(r.NonManifold, want) {
+		t.Errorf("non-manifold want: %v; got: %v", want, r.NonManifold)
+	}
+	if want := []meshcheck.Edge{{0, 2}, {1, 2}}; !reflect.DeepEqual(r.Inconsistent, want) {
+		t.Errorf("inconsistent want: %v; got: %v", want, r.Inconsistent)
+	}
+	if want := []int{5}; !reflect.DeepEqual(r.Degenerate, want) {
+		t.Errorf("degenerate want: %v; got: %v", want, r.Degenerate)
+	}
+}
+
+func Test_CheckInvalid(t *testing.T) {
+	if _, err := meshcheck.Check(tetraVerts, []int32{0, 1, 4}); err != meshcheck.ErrInvalid {
+		t.Errorf("want: %v; got: %v", meshcheck.ErrInvalid, err)
+	}
+}
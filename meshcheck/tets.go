@@ -0,0 +1,159 @@
+package meshcheck
+
+import (
+	"fmt"
+	"sort"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+	"neilpa.me/cgo-shewchuk-robust/internal/parallel"
+)
+
+// Face is an undirected tetrahedral mesh face as a sorted triple of
+// vertex indices.
+type Face [3]int32
+
+// TetReport lists the problems found in a tetrahedral mesh. Every list
+// is sorted.
+type TetReport struct {
+	// Tets is the number of tetrahedra checked.
+	Tets int
+	// Inverted holds the tetrahedra with negative orientation.
+	Inverted []int
+	// Flat holds the tetrahedra whose vertices are coplanar.
+	Flat []int
+	// NonDelaunay holds the pairs of adjacent tetrahedra (i < j) whose
+	// shared face isn't locally Delaunay, i.e. the opposite vertex of one
+	// lies strictly inside the circumsphere of the other.
+	NonDelaunay [][2]int
+	// NonManifold holds the faces shared by more than two tetrahedra.
+	NonManifold []Face
+}
+
+// Valid reports whether no problems were found.
+func (r *TetReport) Valid() bool {
+	return len(r.Inverted) == 0 && len(r.Flat) == 0 &&
+		len(r.NonDelaunay) == 0 && len(r.NonManifold) == 0
+}
+
+// String summarizes the report.
+func (r *TetReport) String() string {
+	return fmt.Sprintf("%d tetrahedra: %d inverted, %d flat, %d non-Delaunay faces, %d non-manifold faces",
+		r.Tets, len(r.Inverted), len(r.Flat), len(r.NonDelaunay), len(r.NonManifold))
+}
+
+// CheckTets validates a tetrahedral mesh given as a flat `[]float64`
+// vertex buffer of XYZ triples and a flat `[]int32` index buffer of
+// vertex quadruples per tetrahedron. A tetrahedron (a, b, c, d) is
+// positively oriented when `robust.Orient3(a, b, c, d)` is positive.
+//
+// Orientation is checked with `robust.Orient3` and every interior face
+// between two positively oriented tetrahedra with `robust.InSphere`. Both
+// passes are split across GOMAXPROCS goroutines.
+func CheckTets(verts []float64, tets []int32) (*TetReport, error) {
+	if len(verts)%3 != 0 || len(tets)%4 != 0 {
+		return nil, ErrInvalid
+	}
+	nverts := int32(len(verts) / 3)
+	for _, v := range tets {
+		if v < 0 || v >= nverts {
+			return nil, ErrInvalid
+		}
+	}
+	vertex := func(v int32) []float64 { return verts[3*v : 3*v+3] }
+	n := len(tets) / 4
+	r := &TetReport{Tets: n}
+
+	orient := make([]int8, n)
+	parallel.For(n, func(lo, hi int) {
+		for t := lo; t < hi; t++ {
+			v := tets[4*t : 4*t+4]
+			det := robust.Orient3(vertex(v[0]), vertex(v[1]), vertex(v[2]), vertex(v[3]))
+			if det > 0 {
+				orient[t] = 1
+			} else if det < 0 {
+				orient[t] = -1
+			}
+		}
+	})
+	for t, o := range orient {
+		if o < 0 {
+			r.Inverted = append(r.Inverted, t)
+		} else if o == 0 {
+			r.Flat = append(r.Flat, t)
+		}
+	}
+
+	// Pair up the tetrahedra on each side of every face
+	type side struct{ tet, apex int }
+	faces := make(map[Face][]side, 2*n)
+	for t := 0; t < n; t++ {
+		v := tets[4*t : 4*t+4]
+		for k := 0; k < 4; k++ {
+			f := sortFace(v[(k+1)%4], v[(k+2)%4], v[(k+3)%4])
+			faces[f] = append(faces[f], side{t, k})
+		}
+	}
+	var interior [][2]side
+	for f, sides := range faces {
+		switch {
+		case len(sides) > 2:
+			r.NonManifold = append(r.NonManifold, f)
+		case len(sides) == 2:
+			interior = append(interior, [2]side{sides[0], sides[1]})
+		}
+	}
+
+	violations := make([]bool, len(interior))
+	parallel.For(len(interior), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			s, o := interior[i][0], interior[i][1]
+			if orient[s.tet] <= 0 || orient[o.tet] <= 0 {
+				continue
+			}
+			v := tets[4*s.tet : 4*s.tet+4]
+			e := vertex(tets[4*o.tet+o.apex])
+			det := robust.InSphere(vertex(v[0]), vertex(v[1]), vertex(v[2]), vertex(v[3]), e)
+			violations[i] = det > 0
+		}
+	})
+	for i, bad := range violations {
+		if bad {
+			a, b := interior[i][0].tet, interior[i][1].tet
+			if a > b {
+				a, b = b, a
+			}
+			r.NonDelaunay = append(r.NonDelaunay, [2]int{a, b})
+		}
+	}
+
+	sort.Slice(r.NonDelaunay, func(i, j int) bool {
+		if r.NonDelaunay[i][0] != r.NonDelaunay[j][0] {
+			return r.NonDelaunay[i][0] < r.NonDelaunay[j][0]
+		}
+		return r.NonDelaunay[i][1] < r.NonDelaunay[j][1]
+	})
+	sort.Slice(r.NonManifold, func(i, j int) bool {
+		a, b := r.NonManifold[i], r.NonManifold[j]
+		for k := 0; k < 3; k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return r, nil
+}
+
+// sortFace returns the face of the vertices in increasing order.
+func sortFace(a, b, c int32) Face {
+	if a > b {
+		a, b = b, a
+	}
+	if b > c {
+		b, c = c, b
+	}
+	if a > b {
+		a, b = b, a
+	}
+	return Face{a, b, c}
+}
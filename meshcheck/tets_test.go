@@ -0,0 +1,94 @@
+package meshcheck_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"neilpa.me/cgo-shewchuk-robust/meshcheck"
+)
+
+func Test_CheckTets(t *testing.T) {
+	// Two tetrahedra on either side of the face 0-1-2. The low apex is
+	// inside the circumsphere of the tall tetrahedron.
+	verts := []float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 0.2, 0.2, 1, 0.2, 0.2, -0.01}
+	tets := []int32{0, 2, 1, 3, 0, 1, 2, 4}
+
+	r, err := meshcheck.CheckTets(verts, tets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Inverted)+len(r.Flat)+len(r.NonManifold) != 0 {
+		t.Errorf("unexpected problems: %v", r)
+	}
+	if want := [][2]int{{0, 1}}; !reflect.DeepEqual(r.NonDelaunay, want) {
+		t.Errorf("non-Delaunay want: %v; got: %v", want, r.NonDelaunay)
+	}
+
+	// Flatten and invert the tall one instead
+	verts[11] = 0
+	tets = []int32{0, 2, 1, 3, 0, 2, 1, 4}
+	r, err = meshcheck.CheckTets(verts, tets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1}; !reflect.DeepEqual(r.Inverted, want) {
+		t.Errorf("inverted want: %v; got: %v", want, r.Inverted)
+	}
+	if want := []int{0}; !reflect.DeepEqual(r.Flat, want) {
+		t.Errorf("flat want: %v; got: %v", want, r.Flat)
+	}
+}
+
+func Test_CheckTetsGrid(t *testing.T) {
+	// Cubes on an integer grid each split into six tetrahedra around the
+	// main diagonal. All eight corners are cospherical so every face is
+	// (degenerately) Delaunay.
+	const n = 6
+	var verts []float64
+	for z := 0; z <= n; z++ {
+		for y := 0; y <= n; y++ {
+			for x := 0; x <= n; x++ {
+				verts = append(verts, float64(x), float64(y), float64(z))
+			}
+		}
+	}
+	id := func(x, y, z int) int32 { return int32(x + (n+1)*(y+(n+1)*z)) }
+	var tets []int32
+	for z := 0; z < n; z++ {
+		for y := 0; y < n; y++ {
+			for x := 0; x < n; x++ {
+				c := func(dx, dy, dz int) int32 { return id(x+dx, y+dy, z+dz) }
+				o, d := c(0, 0, 0), c(1, 1, 1)
+				path := []int32{c(1, 0, 0), c(1, 1, 0), c(0, 1, 0), c(0, 1, 1), c(0, 0, 1), c(1, 0, 1)}
+				for k := range path {
+					tets = append(tets, o, path[(k+1)%6], path[k], d)
+				}
+			}
+		}
+	}
+
+	r, err := meshcheck.CheckTets(verts, tets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Valid() {
+		t.Fatalf("want: valid; got: %v", r)
+	}
+
+	// Nudging interior vertices breaks the cospherical ties, so some
+	// faces become non-Delaunay.
+	rnd := rand.New(rand.NewSource(1))
+	for i := range verts {
+		if verts[i] > 0 && verts[i] < n {
+			verts[i] += (rnd.Float64() - 0.5) * 0.1
+		}
+	}
+	r, err = meshcheck.CheckTets(verts, tets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Inverted)+len(r.Flat) != 0 || len(r.NonDelaunay) == 0 {
+		t.Errorf("want: only non-Delaunay faces; got: %v", r)
+	}
+}
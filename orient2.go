@@ -12,25 +12,49 @@ import "C"
 //
 // Each slice parameter must contain at least 2 values.
 func Orient2(a, b, c []float64) float64 {
-	detleft := (a[0] - c[0]) * (b[1] - c[1])
-	detright := (a[1] - c[1]) * (b[0] - c[0])
 	pa := (*C.double)(&a[0])
 	pb := (*C.double)(&b[0])
 	pc := (*C.double)(&c[0])
 
-	return orient2(pa, pb, pc, detleft, detright)
+	return orient2(pa, pb, pc,
+		a[0]-c[0], b[1]-c[1],
+		a[1]-c[1], b[0]-c[0],
+	)
 }
 
 // Orient2Vec is similiar to `Orient2` but takes a point-like struct
 // pointer rather than a slice.
 func Orient2Vec(a, b, c *XY) float64 {
-	detleft := (a.X - c.X) * (b.Y - c.Y)
-	detright := (a.Y - c.Y) * (b.X - c.X)
 	pa := (*C.double)(&a.X)
 	pb := (*C.double)(&b.X)
 	pc := (*C.double)(&c.X)
 
-	return orient2(pa, pb, pc, detleft, detright)
+	return orient2(pa, pb, pc,
+		a.X-c.X, b.Y-c.Y,
+		a.Y-c.Y, b.X-c.X,
+	)
+}
+
+// Orient2At is similar to `Orient2` but takes the points at indices i, j,
+// and k of a buffer of interleaved points, where point i starts at
+// buf[i*stride]. The bounds of all three points are checked once up
+// front. The stride must be at least 2.
+func Orient2At(buf []float64, stride, i, j, k int) float64 {
+	if n := len(buf); stride < 2 ||
+		!inAt(n, stride, 2, i) ||
+		!inAt(n, stride, 2, j) ||
+		!inAt(n, stride, 2, k) {
+		panicAt(buf, stride, 2, i, j, k)
+	}
+	i, j, k = i*stride, j*stride, k*stride
+	pa := (*C.double)(&buf[i])
+	pb := (*C.double)(&buf[j])
+	pc := (*C.double)(&buf[k])
+
+	return orient2(pa, pb, pc,
+		buf[i]-buf[k], buf[j+1]-buf[k+1],
+		buf[i+1]-buf[k+1], buf[j]-buf[k],
+	)
 }
 
 // Orient2Ptr is the direct wrapper of `orient2d` from `predicates.c`.
@@ -44,30 +68,10 @@ func Orient2Ptr(a, b, c *float64) float64 {
 
 // orient2 implements the basic error bound checks to minimize
 // CGO calls to the adaptive implementation.
-func orient2(pa, pb, pc *C.double, detleft, detright float64) float64 {
-	var detsum float64
-	det := detleft - detright
-
-	if detleft > 0.0 {
-		if detright <= 0.0 {
-			return det
-		} else {
-			detsum = detleft + detright
-		}
-	} else if detleft < 0.0 {
-		if detright >= 0.0 {
-			return det
-		} else {
-			detsum = -detleft - detright
-		}
-	} else {
+func orient2(pa, pb, pc *C.double, acx, bcy, acy, bcx float64) float64 {
+	det, detsum, ok := orient2A(acx, bcy, acy, bcx)
+	if ok {
 		return det
 	}
-
-	errbound := ccwerrboundA * detsum
-	if (det >= errbound) || (-det >= errbound) {
-		return det
-	}
-
 	return float64(C.orient2dadapt(pa, pb, pc, C.double(detsum)))
 }
@@ -1,6 +1,7 @@
 package robust_test
 
 import (
+	"math/bits"
 	"testing"
 
 	robust "neilpa.me/cgo-shewchuk-robust"
@@ -24,6 +25,9 @@ func Test_Orient2(t *testing.T) {
 
 			res = robust.Orient2Vec((*robust.XY)(&va), (*robust.XY)(&vb), (*robust.XY)(&vc))
 			assert(t, tt.sign, res)
+
+			res = robust.Orient2At(interleave(5, a, b, c), 5, 2, 1, 0)
+			assert(t, tt.sign, res)
 		})
 	}
 }
@@ -92,3 +96,57 @@ func Benchmark_Orient2Vec(b *testing.B) {
 	}
 	result = res
 }
+
+func Test_Orient2AtBounds(t *testing.T) {
+	buf := []float64{0, 0, 9, 1, 0, 9, 0, 1}
+	if allocs := testing.AllocsPerRun(100, func() { result = robust.Orient2At(buf, 3, 0, 1, 2) }); allocs != 0 {
+		t.Errorf("want 0 allocs; got %g", allocs)
+	}
+	assert(t, 1, robust.Orient2At(buf, 3, 0, 1, 2))
+
+	for _, tt := range []struct {
+		label     string
+		stride, i int
+	}{
+		{"negative", 3, -1},
+		{"past end", 3, 3},
+		{"partial", 4, 2},
+		{"stride", 1, 0},
+	} {
+		t.Run(tt.label, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("want panic")
+				}
+			}()
+			robust.Orient2At(buf, tt.stride, 0, tt.i, 1)
+		})
+	}
+
+	// i*stride wraps around to 0 for the last two points
+	t.Run("wrapped", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("want panic")
+			}
+		}()
+		robust.Orient2At(buf, 1<<(bits.UintSize-2), 0, 4, 4)
+	})
+}
+
+func Benchmark_Orient2At(b *testing.B) {
+	fixtures := loadCases(b, "orient2.txt", 6)
+	var buf []float64
+	for _, tt := range fixtures {
+		buf = append(buf, tt.args...)
+	}
+
+	b.ResetTimer()
+	var res float64
+	for n := 0; n < b.N; n++ {
+		for i := range fixtures {
+			res = robust.Orient2At(buf, 2, 3*i, 3*i+1, 3*i+2)
+		}
+	}
+	result = res
+}
@@ -3,7 +3,6 @@ package robust
 // double orient3d(double *pa, double *pb, double *pc, double *pd);
 // double orient3dadapt(double *pa, double *pb, double *pc, double *pd, double permanent);
 import "C"
-import "math"
 
 // Orient3D returns a positive value if the point d lies below the
 // plane passing through a, b, and c; "below" is defined so that a, b,
@@ -42,6 +41,31 @@ func Orient3Vec(a, b, c, d *XYZ) float64 {
 	)
 }
 
+// Orient3At is similar to `Orient3` but takes the points at indices i, j,
+// k, and l of a buffer of interleaved points, where point i starts at
+// buf[i*stride]. The bounds of all four points are checked once up front.
+// The stride must be at least 3.
+func Orient3At(buf []float64, stride, i, j, k, l int) float64 {
+	if n := len(buf); stride < 3 ||
+		!inAt(n, stride, 3, i) ||
+		!inAt(n, stride, 3, j) ||
+		!inAt(n, stride, 3, k) ||
+		!inAt(n, stride, 3, l) {
+		panicAt(buf, stride, 3, i, j, k, l)
+	}
+	i, j, k, l = i*stride, j*stride, k*stride, l*stride
+	pa := (*C.double)(&buf[i])
+	pb := (*C.double)(&buf[j])
+	pc := (*C.double)(&buf[k])
+	pd := (*C.double)(&buf[l])
+
+	return orient3(pa, pb, pc, pd,
+		buf[i]-buf[l], buf[j]-buf[l], buf[k]-buf[l],
+		buf[i+1]-buf[l+1], buf[j+1]-buf[l+1], buf[k+1]-buf[l+1],
+		buf[i+2]-buf[l+2], buf[j+2]-buf[l+2], buf[k+2]-buf[l+2],
+	)
+}
+
 // Orient3Ptr is the direct wrapper of `orient3d` from `predicates.c`.
 // See `Orient3` for additional details.
 func Orient3Ptr(a, b, c, d *float64) float64 {
@@ -57,30 +81,9 @@ func Orient3Ptr(a, b, c, d *float64) float64 {
 func orient3(pa, pb, pc, pd *C.double,
 	adx, bdx, cdx, ady, bdy, cdy, adz, bdz, cdz float64,
 ) float64 {
-
-	bdxcdy := bdx * cdy
-	cdxbdy := cdx * bdy
-
-	cdxady := cdx * ady
-	adxcdy := adx * cdy
-
-	adxbdy := adx * bdy
-	bdxady := bdx * ady
-
-	det :=
-		adz*(bdxcdy-cdxbdy) +
-			bdz*(cdxady-adxcdy) +
-			cdz*(adxbdy-bdxady)
-
-	permanent :=
-		(math.Abs(bdxcdy)+math.Abs(cdxbdy))*math.Abs(adz) +
-			(math.Abs(cdxady)+math.Abs(adxcdy))*math.Abs(bdz) +
-			(math.Abs(adxbdy)+math.Abs(bdxady))*math.Abs(cdz)
-
-	errbound := o3derrboundA * permanent
-	if (det > errbound) || (-det > errbound) {
+	det, permanent, ok := orient3A(adx, bdx, cdx, ady, bdy, cdy, adz, bdz, cdz)
+	if ok {
 		return det
 	}
-
 	return float64(C.orient3dadapt(pa, pb, pc, pd, C.double(permanent)))
 }
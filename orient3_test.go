@@ -26,6 +26,9 @@ func Test_Orient3(t *testing.T) {
 
 			res = robust.Orient3Vec((*robust.XYZ)(&va), (*robust.XYZ)(&vb), (*robust.XYZ)(&vc), (*robust.XYZ)(&vd))
 			assert(t, tt.sign, res)
+
+			res = robust.Orient3At(interleave(6, a, b, c, d), 6, 3, 2, 1, 0)
+			assert(t, tt.sign, res)
 		})
 	}
 }
@@ -0,0 +1,104 @@
+package robust
+
+// #include <stdint.h>
+//
+// double orient3dadapt(double *pa, double *pb, double *pc, double *pd, double permanent);
+// double insphereadapt(double *pa, double *pb, double *pc, double *pd, double *pe, double permanent);
+//
+// // The batch functions run the adaptive routines for the pending
+// // elements, whose stage A filters failed, in a single cgo call.
+//
+// static void orient3dbatch(double *verts, int32_t *idx, int32_t *pending, double *permanent, double *out, int n) {
+// 	for (int i = 0; i < n; i++) {
+// 		int32_t *v = idx + 4*pending[i];
+// 		out[pending[i]] = orient3dadapt(verts + 3*v[0], verts + 3*v[1], verts + 3*v[2], verts + 3*v[3], permanent[i]);
+// 	}
+// }
+//
+// static void inspherebatch(double *verts, int32_t *idx, int32_t *pending, double *permanent, double *out, int n) {
+// 	for (int i = 0; i < n; i++) {
+// 		int32_t *v = idx + 5*pending[i];
+// 		out[pending[i]] = insphereadapt(verts + 3*v[0], verts + 3*v[1], verts + 3*v[2], verts + 3*v[3], verts + 3*v[4], permanent[i]);
+// 	}
+// }
+import "C"
+
+import "neilpa.me/cgo-shewchuk-robust/internal/parallel"
+
+// The parallel predicates are safe to run concurrently because
+// `exactinit` only writes the globals of `predicates.c` once, from the
+// package init, and the adaptive routines otherwise only use the stack.
+
+// Orient3Parallel stores `Orient3` for every quadruple of indices in idx
+// into a flat `[]float64` buffer of XYZ points in out, which must hold at
+// least len(idx)/4 values. The work is split across GOMAXPROCS goroutines,
+// each running the Go-side filter and passing the elements it can't
+// decide to the adaptive implementation in a single cgo call. The results
+// are identical to calling `Orient3` for each element.
+//
+// Every index must be in range.
+func Orient3Parallel(verts []float64, idx []int32, out []float64) {
+	n := len(idx) / 4
+	_ = out[:n]
+	parallel.For(n, func(lo, hi int) {
+		var pending []int32
+		var permanents []float64
+		for t := lo; t < hi; t++ {
+			v := idx[4*t : 4*t+4]
+			a, b, c, d := verts[3*v[0]:3*v[0]+3], verts[3*v[1]:3*v[1]+3], verts[3*v[2]:3*v[2]+3], verts[3*v[3]:3*v[3]+3]
+			det, permanent, ok := orient3A(
+				a[0]-d[0], b[0]-d[0], c[0]-d[0],
+				a[1]-d[1], b[1]-d[1], c[1]-d[1],
+				a[2]-d[2], b[2]-d[2], c[2]-d[2],
+			)
+			if ok {
+				out[t] = det
+				continue
+			}
+			pending = append(pending, int32(t))
+			permanents = append(permanents, permanent)
+		}
+		if len(pending) > 0 {
+			C.orient3dbatch(
+				(*C.double)(&verts[0]), (*C.int32_t)(&idx[0]), (*C.int32_t)(&pending[0]),
+				(*C.double)(&permanents[0]), (*C.double)(&out[0]), C.int(len(pending)),
+			)
+		}
+	})
+}
+
+// InSphereParallel stores `InSphere` for every quintuple of indices in
+// idx into a flat `[]float64` buffer of XYZ points in out, which must hold
+// at least len(idx)/5 values. It splits the work like `Orient3Parallel`
+// and the results are identical to calling `InSphere` for each element.
+//
+// Every index must be in range.
+func InSphereParallel(verts []float64, idx []int32, out []float64) {
+	n := len(idx) / 5
+	_ = out[:n]
+	parallel.For(n, func(lo, hi int) {
+		var pending []int32
+		var permanents []float64
+		for t := lo; t < hi; t++ {
+			v := idx[5*t : 5*t+5]
+			a, b, c, d, e := verts[3*v[0]:3*v[0]+3], verts[3*v[1]:3*v[1]+3], verts[3*v[2]:3*v[2]+3], verts[3*v[3]:3*v[3]+3], verts[3*v[4]:3*v[4]+3]
+			det, permanent, ok := inSphereA(
+				a[0]-e[0], b[0]-e[0], c[0]-e[0], d[0]-e[0],
+				a[1]-e[1], b[1]-e[1], c[1]-e[1], d[1]-e[1],
+				a[2]-e[2], b[2]-e[2], c[2]-e[2], d[2]-e[2],
+			)
+			if ok {
+				out[t] = det
+				continue
+			}
+			pending = append(pending, int32(t))
+			permanents = append(permanents, permanent)
+		}
+		if len(pending) > 0 {
+			C.inspherebatch(
+				(*C.double)(&verts[0]), (*C.int32_t)(&idx[0]), (*C.int32_t)(&pending[0]),
+				(*C.double)(&permanents[0]), (*C.double)(&out[0]), C.int(len(pending)),
+			)
+		}
+	})
+}
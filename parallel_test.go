@@ -0,0 +1,122 @@
+package robust_test
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// batch flattens the fixture points into a vertex buffer with an index
+// per point, in order.
+func batch(fixtures []testcase) ([]float64, []int32) {
+	var verts []float64
+	var idx []int32
+	for _, tt := range fixtures {
+		for i := 0; i < len(tt.args); i += 3 {
+			idx = append(idx, int32(len(verts)/3))
+			verts = append(verts, tt.args[i:i+3]...)
+		}
+	}
+	return verts, idx
+}
+
+// nearlyDegenerate returns n points on a small grid, with a tiny offset on
+// some of them, so that many orientations and spheres are degenerate or
+// close to it, along with random indices into them.
+func nearlyDegenerate(rng *rand.Rand, n, m int) ([]float64, []int32) {
+	verts := make([]float64, 3*n)
+	for i := range verts {
+		verts[i] = float64(rng.Intn(4))
+		if rng.Intn(8) == 0 {
+			verts[i] += math.Ldexp(float64(rng.Intn(3)-1), -50)
+		}
+	}
+	idx := make([]int32, m)
+	for i := range idx {
+		idx[i] = int32(rng.Intn(n))
+	}
+	return verts, idx
+}
+
+func Test_Orient3Parallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	rng := rand.New(rand.NewSource(1))
+
+	fv, fi := batch(loadCases(t, "orient3.txt", 12))
+	rv, ri := nearlyDegenerate(rng, 64, 4*10000)
+	for _, tt := range []struct {
+		label string
+		verts []float64
+		idx   []int32
+	}{
+		{"fixtures", fv, fi},
+		{"degenerate", rv, ri},
+		{"single", fv[:12], fi[:4]},
+	} {
+		t.Run(tt.label, func(t *testing.T) {
+			out := make([]float64, len(tt.idx)/4)
+			robust.Orient3Parallel(tt.verts, tt.idx, out)
+			vertex := func(v int32) []float64 { return tt.verts[3*v : 3*v+3] }
+			for i := range out {
+				v := tt.idx[4*i : 4*i+4]
+				want := robust.Orient3(vertex(v[0]), vertex(v[1]), vertex(v[2]), vertex(v[3]))
+				if math.Float64bits(out[i]) != math.Float64bits(want) {
+					t.Fatalf("element %d: want %g; got %g", i, want, out[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_InSphereParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	rng := rand.New(rand.NewSource(1))
+
+	fv, fi := batch(loadCases(t, "insphere.txt", 15))
+	rv, ri := nearlyDegenerate(rng, 64, 5*10000)
+	for _, tt := range []struct {
+		label string
+		verts []float64
+		idx   []int32
+	}{
+		{"fixtures", fv, fi},
+		{"degenerate", rv, ri},
+		{"single", fv[:15], fi[:5]},
+	} {
+		t.Run(tt.label, func(t *testing.T) {
+			out := make([]float64, len(tt.idx)/5)
+			robust.InSphereParallel(tt.verts, tt.idx, out)
+			vertex := func(v int32) []float64 { return tt.verts[3*v : 3*v+3] }
+			for i := range out {
+				v := tt.idx[5*i : 5*i+5]
+				want := robust.InSphere(vertex(v[0]), vertex(v[1]), vertex(v[2]), vertex(v[3]), vertex(v[4]))
+				if math.Float64bits(out[i]) != math.Float64bits(want) {
+					t.Fatalf("element %d: want %g; got %g", i, want, out[i])
+				}
+			}
+		})
+	}
+}
+
+func Benchmark_InSphereParallel(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	verts, idx := nearlyDegenerate(rng, 1024, 5*100000)
+	out := make([]float64, len(idx)/5)
+	b.Run("sequential", func(b *testing.B) {
+		vertex := func(v int32) []float64 { return verts[3*v : 3*v+3] }
+		for n := 0; n < b.N; n++ {
+			for i := range out {
+				v := idx[5*i : 5*i+5]
+				out[i] = robust.InSphere(vertex(v[0]), vertex(v[1]), vertex(v[2]), vertex(v[3]), vertex(v[4]))
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			robust.InSphereParallel(verts, idx, out)
+		}
+	})
+}
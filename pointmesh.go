@@ -0,0 +1,123 @@
+package robust
+
+import "neilpa.me/cgo-shewchuk-robust/internal/sign"
+
+// MeshLocation is the location of a point relative to a closed mesh.
+type MeshLocation int
+
+const (
+	// MeshOutside means the point is strictly outside the mesh.
+	MeshOutside MeshLocation = iota
+	// MeshInside means the point is strictly inside the mesh.
+	MeshInside
+	// MeshSurface means the point lies on a face, edge or vertex.
+	MeshSurface
+)
+
+func (l MeshLocation) String() string {
+	switch l {
+	case MeshOutside:
+		return "outside"
+	case MeshInside:
+		return "inside"
+	case MeshSurface:
+		return "surface"
+	}
+	return "unknown"
+}
+
+// PointInMesh exactly locates the point p relative to the closed
+// triangle mesh given as a flat `[]float64` vertex buffer of XYZ triples
+// and a flat `[]int32` index buffer of vertex triples per face.
+//
+// Points on the surface are detected with `Orient3` and `Orient2`. All
+// other points are classified with the even-odd rule along a ray in +x
+// direction whose origin is symbolically perturbed by (0, ε, ε²). The
+// perturbation means the ray never passes exactly through an edge or a
+// vertex, so every crossing is counted exactly once no matter how the
+// point lines up with the mesh. Face orientation doesn't matter.
+//
+// Every index must be in range. The point p must contain at least 3
+// values.
+func PointInMesh(verts []float64, tris []int32, p []float64) MeshLocation {
+	vertex := func(v int32) []float64 { return verts[3*v : 3*v+3] }
+	pyz := []float64{p[1], p[2]}
+
+	inside := false
+	for f := 0; f+2 < len(tris); f += 3 {
+		a, b, c := vertex(tris[f]), vertex(tris[f+1]), vertex(tris[f+2])
+		side := sign.Of(Orient3(a, b, c, p))
+		if side == 0 && onTriangle(a, b, c, p) {
+			return MeshSurface
+		}
+
+		// The perturbed ray crosses iff the perturbed origin is inside
+		// the yz projection and the plane is ahead of it along +x.
+		ayz, byz, cyz := []float64{a[1], a[2]}, []float64{b[1], b[2]}, []float64{c[1], c[2]}
+		o := sign.Of(Orient2(ayz, byz, cyz))
+		if o == 0 || side != o {
+			continue
+		}
+		if o < 0 {
+			byz, cyz = cyz, byz
+		}
+		if perturbedLeft(ayz, byz, pyz) && perturbedLeft(byz, cyz, pyz) && perturbedLeft(cyz, ayz, pyz) {
+			inside = !inside
+		}
+	}
+	if inside {
+		return MeshInside
+	}
+	return MeshOutside
+}
+
+// perturbedLeft reports whether p + (ε, ε²) is strictly left of the
+// directed line uv in 2D. The tie-break only depends on the edge, and
+// flips with its direction, so the two faces of a shared edge agree.
+func perturbedLeft(u, v, p []float64) bool {
+	switch sign.Of(Orient2(u, v, p)) {
+	case 1:
+		return true
+	case -1:
+		return false
+	}
+	if u[1] != v[1] {
+		return u[1] > v[1]
+	}
+	return v[0] > u[0]
+}
+
+// onTriangle reports whether p, which must be coplanar with abc, lies in
+// the closed triangle. Degenerate triangles are treated as the union of
+// their edges.
+func onTriangle(a, b, c, p []float64) bool {
+	for axis := 2; axis >= 0; axis-- {
+		i, j := (axis+1)%3, (axis+2)%3
+		pa, pb, pc := []float64{a[i], a[j]}, []float64{b[i], b[j]}, []float64{c[i], c[j]}
+		pp := []float64{p[i], p[j]}
+		o := sign.Of(Orient2(pa, pb, pc))
+		if o == 0 {
+			continue
+		}
+		return sign.Of(Orient2(pa, pb, pp)) != -o &&
+			sign.Of(Orient2(pb, pc, pp)) != -o &&
+			sign.Of(Orient2(pc, pa, pp)) != -o
+	}
+	return onSegment3(a, b, p) || onSegment3(b, c, p) || onSegment3(c, a, p)
+}
+
+// onSegment3 reports whether p lies on the closed segment uv.
+func onSegment3(u, v, p []float64) bool {
+	for i := 0; i < 3; i++ {
+		if p[i] < u[i] && p[i] < v[i] || p[i] > u[i] && p[i] > v[i] {
+			return false
+		}
+	}
+	for axis := 0; axis < 3; axis++ {
+		i, j := (axis+1)%3, (axis+2)%3
+		if Orient2([]float64{u[i], u[j]}, []float64{v[i], v[j]}, []float64{p[i], p[j]}) != 0 {
+			return false
+		}
+	}
+	return true
+}
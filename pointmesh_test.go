@@ -0,0 +1,97 @@
+package robust_test
+
+import (
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// cube appends the surface of the axis aligned cube [lo, hi]³ with each
+// square split along a diagonal, so rays aligned with the grid hit edges.
+func cube(verts []float64, tris []int32, lo, hi float64) ([]float64, []int32) {
+	base := int32(len(verts) / 3)
+	for i := 0; i < 8; i++ {
+		v := [3]float64{lo, lo, lo}
+		for k := 0; k < 3; k++ {
+			if i&(1<<uint(k)) != 0 {
+				v[k] = hi
+			}
+		}
+		verts = append(verts, v[:]...)
+	}
+	quads := [][4]int32{
+		{0, 2, 3, 1}, {4, 5, 7, 6}, // z
+		{0, 1, 5, 4}, {2, 6, 7, 3}, // y
+		{0, 4, 6, 2}, {1, 3, 7, 5}, // x
+	}
+	for _, q := range quads {
+		tris = append(tris, base+q[0], base+q[1], base+q[2], base+q[0], base+q[2], base+q[3])
+	}
+	return verts, tris
+}
+
+func Test_PointInMesh(t *testing.T) {
+	// A hollow cube with a cubic cavity
+	verts, tris := cube(nil, nil, 0, 4)
+	verts, tris = cube(verts, tris, 1, 3)
+
+	in := func(v, lo, hi float64) bool { return lo < v && v < hi }
+	on := func(v, lo, hi float64) bool { return v == lo || v == hi }
+	classify := func(p []float64, lo, hi float64) robust.MeshLocation {
+		surface := false
+		for _, v := range p {
+			if !in(v, lo, hi) && !on(v, lo, hi) {
+				return robust.MeshOutside
+			}
+			surface = surface || on(v, lo, hi)
+		}
+		if surface {
+			return robust.MeshSurface
+		}
+		return robust.MeshInside
+	}
+
+	coords := []float64{-1, 0, 0.5, 1, 2, 3, 3.5, 4, 5}
+	for _, x := range coords {
+		for _, y := range coords {
+			for _, z := range coords {
+				p := []float64{x, y, z}
+				want := classify(p, 0, 4)
+				switch classify(p, 1, 3) {
+				case robust.MeshInside:
+					want = robust.MeshOutside
+				case robust.MeshSurface:
+					want = robust.MeshSurface
+				}
+				if got := robust.PointInMesh(verts, tris, p); got != want {
+					t.Errorf("%v want: %v; got: %v", p, want, got)
+				}
+			}
+		}
+	}
+}
+
+func Test_PointInMesh_Tetrahedron(t *testing.T) {
+	verts := []float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1}
+	tris := []int32{0, 2, 1, 0, 1, 3, 0, 3, 2, 1, 2, 3}
+	tests := []struct {
+		label string
+		p     []float64
+		want  robust.MeshLocation
+	}{
+		{"centroid", []float64{0.25, 0.25, 0.25}, robust.MeshInside},
+		{"slanted edge", []float64{0.5, 0.5, 0}, robust.MeshSurface},
+		{"slanted face", []float64{0.25, 0.25, 0.5}, robust.MeshSurface},
+		{"above face", []float64{0.25, 0.25, 0.5000000000000001}, robust.MeshOutside},
+		{"below face", []float64{0.25, 0.25, 0.49999999999999994}, robust.MeshInside},
+		{"apex ray", []float64{-1, 0, 1}, robust.MeshOutside},
+		{"edge ray", []float64{-1, 0.5, 0}, robust.MeshOutside},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			if got := robust.PointInMesh(verts, tris, tt.p); got != tt.want {
+				t.Errorf("want: %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,523 @@
+// Package polybool computes boolean operations on polygons with holes.
+//
+// The inputs are overlaid into a planar arrangement whose topology is
+// decided exactly. The pairs of edges that meet are found with the
+// Bentley–Ottmann sweep in package sweep. Crossings of two edges are
+// never rounded while the arrangement is built: they're kept as pairs of
+// segments, ordered along each segment with `robust.Orient2` and
+// `robust.Orient2LPI` and merged when they coincide, so coincident edges,
+// vertices touching edges and several edges crossing at one point are
+// all handled exactly. Edges are sorted around every vertex the same way
+// and the faces of the arrangement are labeled by walking across them,
+// starting from a ray cast that never meets another vertex.
+//
+// Only the coordinates of crossings are rounded, when the resulting
+// rings are emitted.
+package polybool
+
+import (
+	"sort"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+	"neilpa.me/cgo-shewchuk-robust/internal/sign"
+	"neilpa.me/cgo-shewchuk-robust/sweep"
+)
+
+// Polygon is a set of rings, each a flat `[]float64` buffer of XY pairs
+// without repeating the first point. Inputs may use any orientation and
+// are filled with the even-odd rule. Outputs have counterclockwise outer
+// rings and clockwise holes, so either fill rule gives the same region.
+type Polygon [][]float64
+
+// Union returns the region covered by a or b.
+func Union(a, b Polygon) Polygon {
+	return compute(a, b, func(inA, inB bool) bool { return inA || inB })
+}
+
+// Intersection returns the region covered by both a and b.
+func Intersection(a, b Polygon) Polygon {
+	return compute(a, b, func(inA, inB bool) bool { return inA && inB })
+}
+
+// Difference returns the region covered by a but not by b.
+func Difference(a, b Polygon) Polygon {
+	return compute(a, b, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// XOR returns the region covered by exactly one of a and b.
+func XOR(a, b Polygon) Polygon {
+	return compute(a, b, func(inA, inB bool) bool { return inA != inB })
+}
+
+// compute overlays the polygons and returns the boundary of the faces
+// selected by op. The pairs of edges that meet are found with the exact
+// sweep in package sweep, so only those pairs are classified.
+func compute(a, b Polygon, op func(inA, inB bool) bool) Polygon {
+	g := &graph{ids: make(map[[2]float64]int)}
+	g.addPolygon(a, 0)
+	g.addPolygon(b, 1)
+	g.intersect()
+	g.split()
+	g.build()
+	g.label()
+
+	in := func(f int) bool { return op(g.status[f][0], g.status[f][1]) }
+	boundary := func(h int) bool { return in(g.face[h]) && !in(g.face[h^1]) }
+	var out Polygon
+	used := make([]bool, len(g.origin))
+	for start := range g.origin {
+		if used[start] || !boundary(start) {
+			continue
+		}
+		var ring []float64
+		for h := start; !used[h]; {
+			used[h] = true
+			ring = append(ring, g.coords(g.origin[h])...)
+
+			// Turn clockwise around the end vertex to the next boundary
+			// edge, which keeps the result on the left.
+			hs := g.out[g.origin[h^1]]
+			k := g.pos[h^1]
+			for {
+				k = (k + len(hs) - 1) % len(hs)
+				if boundary(hs[k]) {
+					break
+				}
+			}
+			h = hs[k]
+		}
+		out = append(out, ring)
+	}
+	return out
+}
+
+// segment is an input edge from a to b along with the cuts where other
+// edges meet its interior.
+type segment struct {
+	a, b   []float64
+	na, nb int
+	src    int
+	cuts   []cut
+}
+
+// cut is a point in the interior of a segment, either an explicit input
+// point or the crossing with another segment.
+type cut struct {
+	node  int
+	p     []float64
+	other int
+}
+
+// vertex is an arrangement vertex, either an explicit input point or the
+// proper crossing of the segments s and t.
+type vertex struct {
+	p    []float64
+	s, t int
+}
+
+// graph is the arrangement of the input segments. Edge e has the half
+// edges 2e and 2e+1, so the twin of h is h^1.
+type graph struct {
+	segs   []segment
+	verts  []vertex
+	parent []int
+	ids    map[[2]float64]int
+
+	// Per edge, whether each polygon has an odd number of input edges
+	// along it, i.e. whether crossing it toggles being inside.
+	parity [][2]bool
+	// Per half edge, the origin vertex, an input point ahead of it along
+	// its line, its position in the out list of the origin and its face.
+	origin []int
+	target [][]float64
+	pos    []int
+	face   []int
+	// Per vertex, the outgoing half edges counterclockwise.
+	out [][]int
+	// Per face, whether it's inside each polygon.
+	status [][2]bool
+}
+
+// explicit returns the vertex of the input point p.
+func (g *graph) explicit(p []float64) int {
+	key := [2]float64{p[0] + 0, p[1] + 0}
+	if id, ok := g.ids[key]; ok {
+		return id
+	}
+	id := g.newVertex(vertex{p: p})
+	g.ids[key] = id
+	return id
+}
+
+func (g *graph) newVertex(v vertex) int {
+	g.verts = append(g.verts, v)
+	g.parent = append(g.parent, len(g.parent))
+	return len(g.verts) - 1
+}
+
+func (g *graph) find(v int) int {
+	for g.parent[v] != v {
+		g.parent[v] = g.parent[g.parent[v]]
+		v = g.parent[v]
+	}
+	return v
+}
+
+// union merges coincident vertices, keeping an explicit point as the
+// representative when there is one.
+func (g *graph) union(u, v int) {
+	u, v = g.find(u), g.find(v)
+	if u == v {
+		return
+	}
+	if g.verts[u].p == nil {
+		u, v = v, u
+	}
+	g.parent[v] = u
+}
+
+func (g *graph) addPolygon(poly Polygon, src int) {
+	for _, ring := range poly {
+		n := len(ring) / 2
+		for i := 0; i < n; i++ {
+			j := (i + 1) % n
+			a, b := ring[2*i:2*i+2], ring[2*j:2*j+2]
+			if a[0] == b[0] && a[1] == b[1] {
+				continue
+			}
+			g.segs = append(g.segs, segment{a: a, b: b, na: g.explicit(a), nb: g.explicit(b), src: src})
+		}
+	}
+}
+
+// intersect records where the segments meet. The meeting pairs come from
+// `sweep.Intersections`; endpoints lying in the interior of another
+// segment cut it, including along collinear overlaps, and proper
+// crossings become new vertices.
+func (g *graph) intersect() {
+	flat := make([]float64, 0, 4*len(g.segs))
+	for i := range g.segs {
+		flat = append(flat, g.segs[i].a...)
+		flat = append(flat, g.segs[i].b...)
+	}
+	for _, pair := range sweep.Intersections(flat) {
+		i, j := pair[0], pair[1]
+		s, t := &g.segs[i], &g.segs[j]
+		o1 := sign.Of(robust.Orient2(s.a, s.b, t.a))
+		o2 := sign.Of(robust.Orient2(s.a, s.b, t.b))
+		o3 := sign.Of(robust.Orient2(t.a, t.b, s.a))
+		o4 := sign.Of(robust.Orient2(t.a, t.b, s.b))
+		if o1*o2 < 0 && o3*o4 < 0 {
+			v := g.newVertex(vertex{s: i, t: j})
+			s.cuts = append(s.cuts, cut{node: v, other: j})
+			t.cuts = append(t.cuts, cut{node: v, other: i})
+			continue
+		}
+		if o1 == 0 && inside(t.a, s) {
+			s.cuts = append(s.cuts, cut{node: t.na, p: t.a})
+		}
+		if o2 == 0 && inside(t.b, s) {
+			s.cuts = append(s.cuts, cut{node: t.nb, p: t.b})
+		}
+		if o3 == 0 && inside(s.a, t) {
+			t.cuts = append(t.cuts, cut{node: s.na, p: s.a})
+		}
+		if o4 == 0 && inside(s.b, t) {
+			t.cuts = append(t.cuts, cut{node: s.nb, p: s.b})
+		}
+	}
+}
+
+// split sorts the cuts along every segment and merges the ones that
+// coincide.
+func (g *graph) split() {
+	for i := range g.segs {
+		s := &g.segs[i]
+		sort.Slice(s.cuts, func(j, k int) bool { return g.compare(s, s.cuts[j], s.cuts[k]) < 0 })
+		for k := 1; k < len(s.cuts); k++ {
+			if g.compare(s, s.cuts[k-1], s.cuts[k]) == 0 {
+				g.union(s.cuts[k-1].node, s.cuts[k].node)
+			}
+		}
+	}
+}
+
+// compare orders the cuts x and y along s from a to b.
+func (g *graph) compare(s *segment, x, y cut) int {
+	switch {
+	case x.p != nil && y.p != nil:
+		// Both lie on s, where lexicographic order is monotone
+		if lexLess(s.a, s.b) {
+			return lexCompare(x.p, y.p)
+		}
+		return lexCompare(y.p, x.p)
+	case x.p != nil:
+		return g.compareCrossing(s, x.p, y.other)
+	case y.p != nil:
+		return -g.compareCrossing(s, y.p, x.other)
+	}
+	// y follows x iff it lies on the same side of x's crossing line as b
+	l1, l2 := &g.segs[x.other], &g.segs[y.other]
+	side := sign.Of(robust.Orient2LPI(l1.a, l1.b, [2][]float64{s.a, s.b}, [2][]float64{l2.a, l2.b}))
+	switch side {
+	case 0:
+		return 0
+	case sign.Of(robust.Orient2(l1.a, l1.b, s.b)):
+		return -1
+	}
+	return 1
+}
+
+// compareCrossing orders the point p on s against the crossing of s with
+// the segment other. It precedes the crossing iff it lies on the same
+// side of the crossing line as a.
+func (g *graph) compareCrossing(s *segment, p []float64, other int) int {
+	l := &g.segs[other]
+	side := sign.Of(robust.Orient2(l.a, l.b, p))
+	switch side {
+	case 0:
+		return 0
+	case sign.Of(robust.Orient2(l.a, l.b, s.a)):
+		return -1
+	}
+	return 1
+}
+
+// build splits the segments into edges between consecutive vertices,
+// merging overlapping ones, and sorts the edges around every vertex.
+func (g *graph) build() {
+	edges := make(map[[2]int]int)
+	g.out = make([][]int, len(g.verts))
+	for i := range g.segs {
+		s := &g.segs[i]
+		chain := []int{g.find(s.na)}
+		for _, c := range s.cuts {
+			if v := g.find(c.node); v != chain[len(chain)-1] {
+				chain = append(chain, v)
+			}
+		}
+		chain = append(chain, g.find(s.nb))
+
+		for k := 1; k < len(chain); k++ {
+			u, w := chain[k-1], chain[k]
+			if u == w {
+				continue
+			}
+			key, forward := [2]int{u, w}, true
+			if u > w {
+				key, forward = [2]int{w, u}, false
+			}
+			e, ok := edges[key]
+			if !ok {
+				e = len(g.parity)
+				edges[key] = e
+				g.parity = append(g.parity, [2]bool{})
+				g.origin = append(g.origin, key[0], key[1])
+				if forward {
+					g.target = append(g.target, s.b, s.a)
+				} else {
+					g.target = append(g.target, s.a, s.b)
+				}
+				g.out[key[0]] = append(g.out[key[0]], 2*e)
+				g.out[key[1]] = append(g.out[key[1]], 2*e+1)
+			}
+			g.parity[e][s.src] = !g.parity[e][s.src]
+		}
+	}
+
+	g.pos = make([]int, len(g.origin))
+	for v, hs := range g.out {
+		g.sortAround(v, hs)
+		for k, h := range hs {
+			g.pos[h] = k
+		}
+	}
+}
+
+// orient is `robust.Orient2` of the vertex v followed by p and q.
+func (g *graph) orient(v int, p, q []float64) int {
+	x := &g.verts[v]
+	if x.p != nil {
+		return sign.Of(robust.Orient2(x.p, p, q))
+	}
+	s, t := &g.segs[x.s], &g.segs[x.t]
+	return sign.Of(robust.Orient2LPI(p, q, [2][]float64{s.a, s.b}, [2][]float64{t.a, t.b}))
+}
+
+// sortAround sorts the half edges leaving v counterclockwise by angle,
+// starting from the first. Each is ranked by the half plane it falls in
+// relative to the first and then by orientation within it.
+func (g *graph) sortAround(v int, hs []int) {
+	if len(hs) < 2 {
+		return
+	}
+	ref := g.target[hs[0]]
+	type ranked struct{ h, half int }
+	r := make([]ranked, len(hs))
+	for k, h := range hs {
+		r[k] = ranked{h, 3}
+		switch {
+		case k == 0:
+			r[k].half = 0
+		case g.orient(v, ref, g.target[h]) > 0:
+			r[k].half = 1
+		case g.orient(v, ref, g.target[h]) == 0:
+			r[k].half = 2
+		}
+	}
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].half != r[j].half {
+			return r[i].half < r[j].half
+		}
+		return g.orient(v, g.target[r[i].h], g.target[r[j].h]) > 0
+	})
+	for k := range r {
+		hs[k] = r[k].h
+	}
+}
+
+// label finds the faces and whether they're inside each polygon. The
+// outer face of every connected component is found at its
+// lexicographically smallest vertex, which is always explicit, and
+// labeled by a ray cast against the other components. Labels then spread
+// across the edges of the component.
+func (g *graph) label() {
+	n := len(g.origin)
+	g.face = make([]int, n)
+	for h := range g.face {
+		g.face[h] = -1
+	}
+	var faces [][]int
+	for start := range g.face {
+		if g.face[start] >= 0 {
+			continue
+		}
+		f := len(faces)
+		var hs []int
+		for h := start; g.face[h] < 0; {
+			g.face[h] = f
+			hs = append(hs, h)
+			out := g.out[g.origin[h^1]]
+			h = out[(g.pos[h^1]+len(out)-1)%len(out)]
+		}
+		faces = append(faces, hs)
+	}
+
+	comp := make([]int, len(g.verts))
+	for v := range comp {
+		comp[v] = -1
+	}
+	g.status = make([][2]bool, len(faces))
+	seen := make([]bool, len(faces))
+	for v0, hs := range g.out {
+		if comp[v0] >= 0 || len(hs) == 0 {
+			continue
+		}
+		// Collect the component and its smallest vertex
+		c, min := v0, -1
+		comp[v0] = c
+		stack := []int{v0}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if p := g.verts[v].p; p != nil && (min < 0 || lexLess(p, g.verts[min].p)) {
+				min = v
+			}
+			for _, h := range g.out[v] {
+				if w := g.origin[h^1]; comp[w] < 0 {
+					comp[w] = c
+					stack = append(stack, w)
+				}
+			}
+		}
+
+		// Every edge leaves min to the right of the negative x-axis, so
+		// the outer face lies left of the most counterclockwise one.
+		p := g.verts[min].p
+		outer := g.out[min][0]
+		for _, h := range g.out[min][1:] {
+			if robust.Orient2(p, g.target[outer], g.target[h]) > 0 {
+				outer = h
+			}
+		}
+		f := g.face[outer]
+		g.status[f] = g.raycast(p, comp, c)
+		seen[f] = true
+
+		queue := []int{f}
+		for len(queue) > 0 {
+			f := queue[0]
+			queue = queue[1:]
+			for _, h := range faces[f] {
+				o := g.face[h^1]
+				if seen[o] {
+					continue
+				}
+				par := g.parity[h/2]
+				g.status[o] = [2]bool{g.status[f][0] != par[0], g.status[f][1] != par[1]}
+				seen[o] = true
+				queue = append(queue, o)
+			}
+		}
+	}
+}
+
+// raycast returns whether the point p is inside each polygon, counting
+// only the segments outside the component c. The ray runs in +x
+// direction from p symbolically moved up, so it never meets an endpoint,
+// and p never lies on the counted segments.
+func (g *graph) raycast(p []float64, comp []int, c int) (in [2]bool) {
+	for i := range g.segs {
+		s := &g.segs[i]
+		if comp[g.find(s.na)] == c {
+			continue
+		}
+		lo, hi := s.a, s.b
+		if lo[1] > hi[1] {
+			lo, hi = hi, lo
+		}
+		if lo[1] <= p[1] && p[1] < hi[1] && robust.Orient2(lo, hi, p) > 0 {
+			in[s.src] = !in[s.src]
+		}
+	}
+	return in
+}
+
+// coords returns the position of the vertex, rounding crossings.
+func (g *graph) coords(v int) []float64 {
+	x := &g.verts[v]
+	if x.p != nil {
+		return x.p
+	}
+	s, t := &g.segs[x.s], &g.segs[x.t]
+	ux, uy := s.b[0]-s.a[0], s.b[1]-s.a[1]
+	vx, vy := t.b[0]-t.a[0], t.b[1]-t.a[1]
+	wx, wy := t.a[0]-s.a[0], t.a[1]-s.a[1]
+	r := (wx*vy - wy*vx) / (ux*vy - uy*vx)
+	return []float64{s.a[0] + r*ux, s.a[1] + r*uy}
+}
+
+// inside reports whether p, which lies on the line of s, is strictly
+// between its endpoints.
+func inside(p []float64, s *segment) bool {
+	if lexLess(s.a, s.b) {
+		return lexLess(s.a, p) && lexLess(p, s.b)
+	}
+	return lexLess(s.b, p) && lexLess(p, s.a)
+}
+
+func lexLess(p, q []float64) bool {
+	return lexCompare(p, q) < 0
+}
+
+func lexCompare(p, q []float64) int {
+	for k := 0; k < 2; k++ {
+		if p[k] < q[k] {
+			return -1
+		}
+		if p[k] > q[k] {
+			return 1
+		}
+	}
+	return 0
+}
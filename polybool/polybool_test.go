@@ -0,0 +1,165 @@
+package polybool_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"neilpa.me/cgo-shewchuk-robust/polybool"
+)
+
+// area returns the signed area of a ring.
+func area(ring []float64) float64 {
+	a := 0.0
+	for i := 0; i < len(ring); i += 2 {
+		j := (i + 2) % len(ring)
+		a += ring[i]*ring[j+1] - ring[j]*ring[i+1]
+	}
+	return a / 2
+}
+
+func rect(x0, y0, x1, y1 float64) []float64 {
+	return []float64{x0, y0, x1, y0, x1, y1, x0, y1}
+}
+
+func Test_Operations(t *testing.T) {
+	square := polybool.Polygon{rect(0, 0, 2, 2)}
+	diamond := polybool.Polygon{{1, -1, 3, 1, 1, 3, -1, 1}}
+	framed := polybool.Polygon{rect(0, 0, 4, 4), rect(1, 1, 3, 3)}
+	bowtie := polybool.Polygon{{0, 0, 2, 2, 2, 0, 0, 2}}
+	above := polybool.Polygon{{-1, 1, 3, 1, 1, 5}}
+
+	type result struct {
+		area  float64
+		rings int
+	}
+	tests := []struct {
+		label                   string
+		a, b                    polybool.Polygon
+		union, inter, diff, xor result
+	}{
+		{"overlap", square, polybool.Polygon{rect(1, 1, 3, 3)},
+			result{7, 1}, result{1, 1}, result{3, 1}, result{6, 2}},
+		{"shared edge", square, polybool.Polygon{rect(2, 0, 4, 2)},
+			result{8, 1}, result{0, 0}, result{4, 1}, result{8, 1}},
+		{"identical", square, polybool.Polygon{rect(2, 2, 0, 0)},
+			result{4, 1}, result{4, 1}, result{0, 0}, result{0, 0}},
+		{"corners on edges", square, diamond,
+			result{8, 1}, result{4, 1}, result{0, 0}, result{4, 4}},
+		{"hole", framed, polybool.Polygon{rect(2, -1, 5, 5)},
+			result{24, 2}, result{6, 1}, result{6, 1}, result{18, 3}},
+		{"inside hole", framed, polybool.Polygon{rect(1.5, 1.5, 2.5, 2.5)},
+			result{13, 3}, result{0, 0}, result{12, 2}, result{13, 3}},
+		{"concurrent crossing", bowtie, above,
+			result{9, 1}, result{1, 2}, result{1, 2}, result{8, 3}},
+		{"disjoint", square, polybool.Polygon{rect(5, 5, 6, 6)},
+			result{5, 2}, result{0, 0}, result{4, 1}, result{5, 2}},
+	}
+	ops := []struct {
+		name string
+		fn   func(a, b polybool.Polygon) polybool.Polygon
+	}{
+		{"union", polybool.Union},
+		{"intersection", polybool.Intersection},
+		{"difference", polybool.Difference},
+		{"xor", polybool.XOR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			for i, want := range []result{tt.union, tt.inter, tt.diff, tt.xor} {
+				got := ops[i].fn(tt.a, tt.b)
+				total := 0.0
+				for _, ring := range got {
+					if len(ring) < 6 || area(ring) == 0 {
+						t.Errorf("%s: degenerate ring %v", ops[i].name, ring)
+					}
+					total += area(ring)
+				}
+				if total != want.area || len(got) != want.rings {
+					t.Errorf("%s want: %v; got: area %g in %v", ops[i].name, want, total, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_Orientation(t *testing.T) {
+	framed := polybool.Polygon{rect(0, 0, 4, 4), rect(1, 1, 3, 3)}
+	got := polybool.Union(framed, polybool.Polygon{rect(5, 0, 6, 1)})
+	var outer, holes int
+	for _, ring := range got {
+		if area(ring) > 0 {
+			outer++
+		} else {
+			holes++
+		}
+	}
+	if outer != 2 || holes != 1 {
+		t.Errorf("want: 2 outer rings and 1 hole; got: %v", got)
+	}
+}
+
+// Test_AreaIdentities checks the operations against each other on random
+// polygons snapped to a coarse grid, which are full of shared vertices,
+// collinear edges and crossings through vertices.
+func Test_AreaIdentities(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func() polybool.Polygon {
+		n := 3 + rng.Intn(6)
+		ring := make([]float64, 2*n)
+		for i := range ring {
+			ring[i] = float64(rng.Intn(5))
+		}
+		return polybool.Polygon{ring}
+	}
+	total := func(p polybool.Polygon) float64 {
+		sum := 0.0
+		for _, ring := range p {
+			sum += area(ring)
+		}
+		return sum
+	}
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		// Self intersecting inputs are fine under the even-odd rule, so
+		// the reference areas come from operations against nothing.
+		areaA, areaB := total(polybool.Union(a, nil)), total(polybool.Union(b, nil))
+		union := total(polybool.Union(a, b))
+		inter := total(polybool.Intersection(a, b))
+		diff := total(polybool.Difference(a, b))
+		xor := total(polybool.XOR(a, b))
+		if math.Abs(union+inter-areaA-areaB) > 1e-9 ||
+			math.Abs(diff+inter-areaA) > 1e-9 ||
+			math.Abs(xor-union+inter) > 1e-9 {
+			t.Fatalf("%v %v: A %g B %g union %g inter %g diff %g xor %g",
+				a, b, areaA, areaB, union, inter, diff, xor)
+		}
+	}
+}
+
+// Test_ManyEdges overlays two finely sampled circles, where nearly every
+// pair of edges is disjoint and only a handful cross.
+func Test_ManyEdges(t *testing.T) {
+	circle := func(cx, cy float64, n int) polybool.Polygon {
+		ring := make([]float64, 2*n)
+		for i := 0; i < n; i++ {
+			a := 2 * math.Pi * float64(i) / float64(n)
+			ring[2*i], ring[2*i+1] = cx+math.Cos(a), cy+math.Sin(a)
+		}
+		return polybool.Polygon{ring}
+	}
+	total := func(p polybool.Polygon) float64 {
+		sum := 0.0
+		for _, ring := range p {
+			sum += area(ring)
+		}
+		return sum
+	}
+	a, b := circle(0, 0, 5000), circle(0.5, 0.25, 5000)
+	areaA, areaB := total(a), total(b)
+	union := total(polybool.Union(a, b))
+	inter := total(polybool.Intersection(a, b))
+	if math.Abs(union+inter-areaA-areaB) > 1e-9 || inter <= 0 || union <= areaA {
+		t.Fatalf("A %g B %g union %g inter %g", areaA, areaB, union, inter)
+	}
+}
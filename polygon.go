@@ -0,0 +1,74 @@
+package robust
+
+import "math"
+
+// PolygonArea2 returns twice the signed area of the polygon ring, a flat
+// buffer of XY pairs, following the conventions of `Orient2`: positive
+// for counterclockwise rings. The area is the shoelace sum of the cross
+// products of consecutive vertices, returned both correctly rounded and
+// as an exact expansion like `Orient2Expansion`.
+//
+// The ring is implicitly closed, and repeating the first vertex at the
+// end doesn't change the result. Rings with fewer than 3 vertices have
+// zero area.
+func PolygonArea2(ring []float64) (area float64, exact []float64) {
+	exact = polygonArea2Exact(ring)
+	return roundExpansion(exact), exact
+}
+
+// PolygonOrientation returns the exact sign of `PolygonArea2`, i.e. 1 for
+// counterclockwise rings, -1 for clockwise ones and 0 for degenerate ones.
+// Unlike summing `Orient2` results, tiny slivers are never misclassified.
+func PolygonOrientation(ring []float64) int {
+	n := len(ring) / 2
+	if n < 3 {
+		return 0
+	}
+
+	// The shoelace sum is taken relative to the first vertex, which keeps
+	// the permanent small for rings far from the origin. Its error is at
+	// most (n+4)ε·permanent to first order, counting the rounding of the
+	// differences, which the bound covers with room for the higher order
+	// terms and the rounding of the permanent itself.
+	x0, y0 := ring[0], ring[1]
+	det, permanent := 0.0, 0.0
+	for i := 1; i < n-1; i++ {
+		j := i + 1
+		l := float64((ring[2*i] - x0) * (ring[2*j+1] - y0))
+		r := float64((ring[2*j] - x0) * (ring[2*i+1] - y0))
+		det += l - r
+		permanent += math.Abs(l) + math.Abs(r)
+	}
+	errbound := float64(4*n) * epsilon * permanent
+	if det > errbound {
+		return 1
+	}
+	if -det > errbound {
+		return -1
+	}
+	return signExpansion(polygonArea2Exact(ring))
+}
+
+// polygonArea2Exact is the exact expansion of the shoelace sum, taken
+// relative to the first vertex like the filter of `PolygonOrientation`,
+// so coordinates far from the origin don't overflow the products. It's
+// compressed as it grows, since long rings would otherwise accumulate
+// thousands of components.
+func polygonArea2Exact(ring []float64) []float64 {
+	n := len(ring) / 2
+	sum := []float64{0}
+	if n < 3 {
+		return sum
+	}
+	x0, y0 := ring[0], ring[1]
+	dx, dy := diffExpansion(ring[2], x0), diffExpansion(ring[3], y0)
+	for j := 2; j < n; j++ {
+		jx, jy := diffExpansion(ring[2*j], x0), diffExpansion(ring[2*j+1], y0)
+		sum = sumExpansion(sum, subExpansion(mulExpansion(dx, jy), mulExpansion(jx, dy)))
+		if len(sum) > 32 {
+			sum = compress(sum)
+		}
+		dx, dy = jx, jy
+	}
+	return sum
+}
@@ -0,0 +1,147 @@
+package robust_test
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// ratArea2 is the exact shoelace sum of the ring.
+func ratArea2(ring []float64) *big.Rat {
+	n := len(ring) / 2
+	sum := new(big.Rat)
+	if n < 3 {
+		return sum
+	}
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		xi, yi := new(big.Rat).SetFloat64(ring[2*i]), new(big.Rat).SetFloat64(ring[2*i+1])
+		xj, yj := new(big.Rat).SetFloat64(ring[2*j]), new(big.Rat).SetFloat64(ring[2*j+1])
+		sum.Add(sum, new(big.Rat).Mul(xi, yj))
+		sum.Sub(sum, new(big.Rat).Mul(xj, yi))
+	}
+	return sum
+}
+
+// sliver returns a ring of n points far from the origin that are all
+// nearly on a line, with random perturbations of a few ulps.
+func sliver(rng *rand.Rand, n int) []float64 {
+	x0, y0 := 1e6*rng.Float64(), 1e6*rng.Float64()
+	dx, dy := rng.Float64()-0.5, rng.Float64()-0.5
+	ring := make([]float64, 0, 2*n)
+	for i := 0; i < n; i++ {
+		t := 1e6 * rng.Float64()
+		x, y := x0+t*dx, y0+t*dy
+		x += float64(rng.Intn(3)-1) * math.Ldexp(1, -33)
+		ring = append(ring, x, y)
+	}
+	return ring
+}
+
+func Test_PolygonArea2(t *testing.T) {
+	square := []float64{0, 0, 1, 0, 1, 1, 0, 1}
+	reversed := []float64{0, 1, 1, 1, 1, 0, 0, 0}
+	closed := append(append([]float64{}, square...), 0, 0)
+	for _, tt := range []struct {
+		label string
+		ring  []float64
+		area  float64
+		sign  int
+	}{
+		{"empty", nil, 0, 0},
+		{"segment", []float64{0, 0, 1, 1}, 0, 0},
+		{"square", square, 2, 1},
+		{"reversed", reversed, -2, -1},
+		{"closed", closed, 2, 1},
+		{"collinear", []float64{0, 0, 1, 1, 3, 3}, 0, 0},
+	} {
+		t.Run(tt.label, func(t *testing.T) {
+			area, exact := robust.PolygonArea2(tt.ring)
+			if area != tt.area || exact[len(exact)-1] != tt.area {
+				t.Errorf("want %g; got %g %v", tt.area, area, exact)
+			}
+			if got := robust.PolygonOrientation(tt.ring); got != tt.sign {
+				t.Errorf("want sign %d; got %d", tt.sign, got)
+			}
+		})
+	}
+
+	// Far from the origin the products of raw coordinates overflow, but
+	// the area itself is small.
+	far := []float64{1e160, 1e160, 1e160 + 1e150, 1e160, 1e160, 1e160 + 1e150}
+	want, _ := ratArea2(far).Float64()
+	if area, _ := robust.PolygonArea2(far); area != want || math.IsInf(want, 0) {
+		t.Errorf("far: want %g; got %g", want, area)
+	}
+	if got := robust.PolygonOrientation(far); got != 1 {
+		t.Errorf("far: want sign 1; got %d", got)
+	}
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		ring := make([]float64, 2*(3+rng.Intn(20)))
+		for j := range ring {
+			ring[j] = 1e160 + 1e150*rng.Float64()
+		}
+		want, _ := ratArea2(ring).Float64()
+		if area, _ := robust.PolygonArea2(ring); area != want {
+			t.Fatalf("far ring %d: want %g; got %g", i, want, area)
+		}
+	}
+
+	rng = rand.New(rand.NewSource(1))
+	wrong := 0
+	for i := 0; i < 2000; i++ {
+		ring := sliver(rng, 3+rng.Intn(200))
+		want := ratArea2(ring)
+
+		area, exact := robust.PolygonArea2(ring)
+		sum := new(big.Rat)
+		for _, c := range exact {
+			sum.Add(sum, new(big.Rat).SetFloat64(c))
+		}
+		if sum.Cmp(want) != 0 {
+			t.Fatalf("ring %d: expansion %s; want %s", i, sum.FloatString(40), want.FloatString(40))
+		}
+		rounded, _ := want.Float64()
+		if math.Float64bits(area) != math.Float64bits(rounded) {
+			t.Fatalf("ring %d: want %g; got %g", i, rounded, area)
+		}
+		if got := robust.PolygonOrientation(ring); got != want.Sign() {
+			t.Fatalf("ring %d: want sign %d; got %d", i, want.Sign(), got)
+		}
+
+		naive := 0.0
+		for j := 0; j < len(ring)/2; j++ {
+			k := (j + 1) % (len(ring) / 2)
+			naive += ring[2*j]*ring[2*k+1] - ring[2*k]*ring[2*j+1]
+		}
+		if sign(naive) != want.Sign() {
+			wrong++
+		}
+	}
+	if wrong == 0 {
+		t.Fatal("the slivers never fool the rounded shoelace sum")
+	}
+}
+
+func Benchmark_PolygonOrientation(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	ring := make([]float64, 0, 2*1000)
+	for i := 0; i < 1000; i++ {
+		s, c := math.Sincos(2 * math.Pi * float64(i) / 1000)
+		ring = append(ring, 1e6+c*(1+0.1*rng.Float64()), 1e6+s*(1+0.1*rng.Float64()))
+	}
+	b.Run("orientation", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			result = float64(robust.PolygonOrientation(ring))
+		}
+	})
+	b.Run("area", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			result, _ = robust.PolygonArea2(ring)
+		}
+	})
+}
@@ -21,6 +21,12 @@
 // fail are the corresponding `*adapt` C functions called. This provides
 // the most notable performance impact in the `Orient*` methods.
 //
+// The `*At` suffixed functions take the indices of points in a flat
+// buffer of interleaved points with a stride, such as XYZ positions
+// followed by normals, instead of a slice per point, e.g.
+//
+//	res := robust.Orient3At(verts, 6, i, j, k, l)
+//
 // Finally, there are `*Ptr` suffixed functions that take C-like arrays of
 // at least 2 or 3 `*float64` values. These directly wrap the equivalent
 // C functions and don't do any error bounds checking in go. As such, they
@@ -30,14 +36,32 @@
 package robust
 
 // void exactinit();
+// extern double epsilon, splitter;
 // extern double ccwerrboundA, o3derrboundA, iccerrboundA, isperrboundA;
 import "C"
+import "fmt"
 
 // Cache values from CGO init
 var (
+	epsilon, splitter float64
+
 	ccwerrboundA, o3derrboundA, iccerrboundA, isperrboundA float64
 )
 
+// Error bounds for the predicates implemented in go, following the
+// style of `exactinit`. Each is the leading roundoff coefficient of the
+// filter relative to its permanent, padded for the higher order terms.
+var (
+	cd2errboundA, cd3errboundA   float64
+	dot2errboundA, dot3errboundA float64
+
+	lpi2errboundW, lpi2errboundA float64
+	lpi3errboundW, lpi3errboundA float64
+	tpi3errboundW, tpi3errboundA float64
+
+	cmplpi2errboundA, cmpptlpi2errboundA float64
+)
+
 // XY is a "template" for 2D vector types. It's not intended for use
 // directly but as a pointer cast target. See Orient2Vec and InCircleVec.
 type XY struct {
@@ -50,10 +74,49 @@ type XYZ struct {
 	X, Y, Z float64
 }
 
+// inAt reports whether point i of an interleaved buffer of n values has
+// dim values in range. The first test also rejects negative indices, and
+// dividing instead of computing i*stride keeps huge strides from wrapping
+// an out of range point back into the buffer. The stride must be at
+// least dim.
+func inAt(n, stride, dim, i int) bool {
+	return uint(i) < uint(n) && n >= dim && i <= (n-dim)/stride
+}
+
+// panicAt reports invalid arguments to the `*At` functions.
+func panicAt(buf []float64, stride, dim int, idx ...int) {
+	if stride < dim {
+		panic(fmt.Sprintf("robust: stride %d is less than %d", stride, dim))
+	}
+	panic(fmt.Sprintf("robust: point indices %v out of range for %d values with stride %d", idx, len(buf), stride))
+}
+
 func init() {
 	C.exactinit()
+	epsilon = float64(C.epsilon)
+	splitter = float64(C.splitter)
 	ccwerrboundA = float64(C.ccwerrboundA)
 	o3derrboundA = float64(C.o3derrboundA)
 	iccerrboundA = float64(C.iccerrboundA)
 	isperrboundA = float64(C.isperrboundA)
+
+	// Squared differences round three times and each sum once more
+	cd2errboundA = (5.0 + 48.0*epsilon) * epsilon
+	cd3errboundA = (6.0 + 64.0*epsilon) * epsilon
+
+	// Products of differences round three times and each sum once more
+	dot2errboundA = (4.0 + 32.0*epsilon) * epsilon
+	dot3errboundA = (5.0 + 48.0*epsilon) * epsilon
+
+	// Indirect predicates use the depth k of the longest chain of
+	// roundings, where products add the depths of their operands, for a
+	// bound of (k + 4k²ε)ε on both the denominator W and the determinant.
+	lpi2errboundW = (4.0 + 64.0*epsilon) * epsilon
+	lpi2errboundA = (10.0 + 400.0*epsilon) * epsilon
+	lpi3errboundW = (8.0 + 256.0*epsilon) * epsilon
+	lpi3errboundA = (18.0 + 1296.0*epsilon) * epsilon
+	tpi3errboundW = (17.0 + 1156.0*epsilon) * epsilon
+	tpi3errboundA = (28.0 + 3136.0*epsilon) * epsilon
+	cmplpi2errboundA = (13.0 + 676.0*epsilon) * epsilon
+	cmpptlpi2errboundA = (7.0 + 196.0*epsilon) * epsilon
 }
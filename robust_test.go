@@ -2,6 +2,7 @@ package robust_test
 
 import (
 	"bufio"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -75,3 +76,25 @@ func sign(n float64) int {
 	}
 	return 0
 }
+
+// interleave lays out the points in reverse order in a buffer with the
+// stride, filling the gaps with NaN.
+func interleave(stride int, pts ...[]float64) []float64 {
+	buf := make([]float64, stride*len(pts))
+	for i := range buf {
+		buf[i] = math.NaN()
+	}
+	for i, p := range pts {
+		copy(buf[stride*(len(pts)-1-i):], p)
+	}
+	return buf
+}
+
+// points splits flat fixture args into dim-sized point slices.
+func points(args []float64, dim int) [][]float64 {
+	pts := make([][]float64, 0, len(args)/dim)
+	for i := 0; i+dim <= len(args); i += dim {
+		pts = append(pts, args[i:i+dim])
+	}
+	return pts
+}
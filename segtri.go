@@ -0,0 +1,101 @@
+package robust
+
+import "neilpa.me/cgo-shewchuk-robust/internal/sign"
+
+// SegTriResult classifies where a segment meets a triangle in 3D.
+type SegTriResult int
+
+const (
+	// SegTriNone means the segment misses the triangle.
+	SegTriNone SegTriResult = iota
+	// SegTriInterior means the segment crosses the triangle's plane at
+	// a point strictly inside the triangle.
+	SegTriInterior
+	// SegTriEdge means the crossing point lies strictly inside an edge.
+	SegTriEdge
+	// SegTriVertex means the crossing point is a vertex.
+	SegTriVertex
+	// SegTriCoplanar means the segment lies in the triangle's plane.
+	// Whether they overlap must be decided in 2D by the caller.
+	SegTriCoplanar
+)
+
+func (r SegTriResult) String() string {
+	switch r {
+	case SegTriNone:
+		return "none"
+	case SegTriInterior:
+		return "interior"
+	case SegTriEdge:
+		return "edge"
+	case SegTriVertex:
+		return "vertex"
+	case SegTriCoplanar:
+		return "coplanar"
+	}
+	return "unknown"
+}
+
+// SegmentTriangle3 exactly classifies where the segment pq meets the
+// triangle abc. For SegTriEdge the returned index i identifies the edge
+// from vertex i to vertex i+1 (mod 3), with a, b, and c numbered 0, 1,
+// and 2. For SegTriVertex it identifies the vertex. It's -1 otherwise.
+//
+// Only the signs of `Orient3` are used: two for the sides of p and q
+// relative to the plane of abc, and three Plücker-style tests for the
+// side of the line pq relative to each edge. Since the same signs are
+// computed for a shared edge or vertex from either adjacent triangle,
+// a ray grazing an edge is reported for both consistently and can be
+// counted exactly once.
+//
+// Each slice parameter must contain at least 3 values.
+func SegmentTriangle3(p, q, a, b, c []float64) (SegTriResult, int) {
+	sp := sign.Of(Orient3(a, b, c, p))
+	sq := sign.Of(Orient3(a, b, c, q))
+	if sp == 0 && sq == 0 {
+		return SegTriCoplanar, -1
+	}
+	if sp == sq {
+		return SegTriNone, -1
+	}
+
+	edges := [3]int{
+		sign.Of(Orient3(p, q, a, b)),
+		sign.Of(Orient3(p, q, b, c)),
+		sign.Of(Orient3(p, q, c, a)),
+	}
+	var pos, neg, zero int
+	for _, s := range edges {
+		switch s {
+		case 1:
+			pos++
+		case -1:
+			neg++
+		default:
+			zero++
+		}
+	}
+	if pos > 0 && neg > 0 {
+		return SegTriNone, -1
+	}
+	switch zero {
+	case 0:
+		return SegTriInterior, -1
+	case 1:
+		for i, s := range edges {
+			if s == 0 {
+				return SegTriEdge, i
+			}
+		}
+	case 2:
+		// The vertex shared by the two zero edges, i.e. the one
+		// following the single non-zero edge.
+		for i, s := range edges {
+			if s != 0 {
+				return SegTriVertex, (i + 2) % 3
+			}
+		}
+	}
+	// All three edge lines meet pq only for a degenerate triangle
+	return SegTriCoplanar, -1
+}
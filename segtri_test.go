@@ -0,0 +1,49 @@
+package robust_test
+
+import (
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+func Test_SegmentTriangle3(t *testing.T) {
+	fixtures := loadCases(t, "segtri.txt", 16)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			pts := points(tt.args[:15], 3)
+			want, wantIdx := robust.SegTriResult(tt.sign), int(tt.args[15])
+
+			got, idx := robust.SegmentTriangle3(pts[0], pts[1], pts[2], pts[3], pts[4])
+			if got != want || idx != wantIdx {
+				t.Errorf("want: %v(%d); got: %v(%d)", want, wantIdx, got, idx)
+			}
+
+			// Reversing the segment or rotating the triangle only
+			// relabels the feature.
+			got, idx = robust.SegmentTriangle3(pts[1], pts[0], pts[3], pts[4], pts[2])
+			if wantIdx >= 0 {
+				wantIdx = (wantIdx + 2) % 3
+			}
+			if got != want || idx != wantIdx {
+				t.Errorf("rotated want: %v(%d); got: %v(%d)", want, wantIdx, got, idx)
+			}
+		})
+	}
+}
+
+func Benchmark_SegmentTriangle3(b *testing.B) {
+	fixtures := loadCases(b, "segtri.txt", 16)
+	tests := make([][][]float64, len(fixtures))
+	for i, tt := range fixtures {
+		tests[i] = points(tt.args[:15], 3)
+	}
+
+	b.ResetTimer()
+	var res robust.SegTriResult
+	for n := 0; n < b.N; n++ {
+		for _, p := range tests {
+			res, _ = robust.SegmentTriangle3(p[0], p[1], p[2], p[3], p[4])
+		}
+	}
+	result = float64(res)
+}
@@ -0,0 +1,178 @@
+package robust
+
+import "math"
+
+// Contour is a polyline where a mesh crosses a plane, given as a flat
+// `[]float64` buffer of XYZ triples. Closed contours don't repeat their
+// first point.
+type Contour struct {
+	Points []float64
+	Closed bool
+}
+
+// SlicePlane cuts the triangle mesh given as a flat `[]float64` vertex
+// buffer of XYZ triples and a flat `[]int32` index buffer of vertex
+// triples per face with the plane through plane[0], plane[1], and
+// plane[2].
+//
+// The topology only depends on the exact sign of `Orient3` for every
+// vertex. Vertices on the plane are treated as lying above it, where
+// above follows the conventions of `Orient3`, so every edge is either
+// crossed or not and shared edges and vertices yield no duplicate or
+// missing segments. A face lying in the plane therefore contributes
+// nothing, and a cross-section that collapses to a point or a segment
+// is dropped.
+//
+// For a closed, consistently oriented manifold mesh whose faces are
+// counterclockwise seen from outside, every contour is closed, with
+// outer boundaries counterclockwise and holes clockwise seen from above.
+// Boundary edges of an open mesh end open contours instead.
+//
+// Each intersection point is constructed from the exact plane distances
+// of its edge endpoints a and b. It's exact when a or b lies on the
+// plane and otherwise each coordinate x differs from the exact value by
+// at most (|x| + 4|b-a|)·ε in that coordinate, where ε is 2^-53. Points
+// are computed once per edge and cached by the edge, so every contour
+// passing through an edge gets the same point.
+//
+// Every index must be in range. Each plane slice must contain at least
+// 3 values.
+func SlicePlane(verts []float64, tris []int32, plane [3][]float64) []Contour {
+	vertex := func(v int32) []float64 { return verts[3*v : 3*v+3] }
+
+	// below holds the side of each vertex while on marks the vertices
+	// lying exactly on the plane, whose cuts are the vertices themselves.
+	nverts := len(verts) / 3
+	below := make([]bool, nverts)
+	on := make([]bool, nverts)
+	for v := 0; v < nverts; v++ {
+		det := Orient3(plane[0], plane[1], plane[2], vertex(int32(v)))
+		below[v] = det > 0
+		on[v] = det == 0
+	}
+
+	// Each crossed face yields a segment from the cut of the edge going
+	// below to the cut of the edge coming back above. Cuts are keyed by
+	// their edge with the smaller vertex index first.
+	type segment struct{ from, to [2]int32 }
+	var segs []segment
+	for f := 0; f+2 < len(tris); f += 3 {
+		t := tris[f : f+3]
+		var s segment
+		crossed := false
+		for k := 0; k < 3; k++ {
+			u, v := t[k], t[(k+1)%3]
+			if below[u] == below[v] {
+				continue
+			}
+			crossed = true
+			if below[v] {
+				s.from = edgeKey(u, v)
+			} else {
+				s.to = edgeKey(u, v)
+			}
+		}
+		if crossed && s.from != s.to {
+			segs = append(segs, s)
+		}
+	}
+
+	next := make(map[[2]int32]int, len(segs))
+	ends := make(map[[2]int32]bool, len(segs))
+	for i, s := range segs {
+		next[s.from] = i
+		ends[s.to] = true
+	}
+
+	normal := crossExpansion(diffVector(plane[1], plane[0]), diffVector(plane[2], plane[0]))
+	cuts := make(map[[2]int32][]float64, len(segs))
+	cut := func(key [2]int32) []float64 {
+		if p, ok := cuts[key]; ok {
+			return p
+		}
+		a, b := vertex(key[0]), vertex(key[1])
+		da := dotExpansion(normal, diffVector(a, plane[0]))
+		db := dotExpansion(normal, diffVector(b, plane[0]))
+		// Interpolate from the nearer endpoint to halve the error
+		if math.Abs(approximate(da)) > math.Abs(approximate(db)) {
+			a, b, da = b, a, db
+		}
+		t := approximate(da) / approximate(dotExpansion(normal, diffVector(a, b)))
+		p := []float64{
+			a[0] + t*(b[0]-a[0]),
+			a[1] + t*(b[1]-a[1]),
+			a[2] + t*(b[2]-a[2]),
+		}
+		cuts[key] = p
+		return p
+	}
+	// onVertex returns the vertex a cut coincides with, or -1.
+	onVertex := func(key [2]int32) int32 {
+		for _, v := range key {
+			if on[v] {
+				return v
+			}
+		}
+		return -1
+	}
+
+	var contours []Contour
+	used := make([]bool, len(segs))
+	walk := func(i int) {
+		start := segs[i].from
+		keys := [][2]int32{start}
+		closed := false
+		for {
+			used[i] = true
+			key := segs[i].to
+			if key == start {
+				closed = true
+				break
+			}
+			keys = append(keys, key)
+			j, ok := next[key]
+			if !ok || used[j] {
+				break
+			}
+			i = j
+		}
+
+		// Consecutive cuts at the same vertex are the same point
+		var pts []float64
+		var at []int32
+		for _, key := range keys {
+			v := onVertex(key)
+			if v >= 0 && len(at) > 0 && at[len(at)-1] == v {
+				continue
+			}
+			pts = append(pts, cut(key)...)
+			at = append(at, v)
+		}
+		if closed && len(at) > 1 && at[0] >= 0 && at[0] == at[len(at)-1] {
+			pts, at = pts[:len(pts)-3], at[:len(at)-1]
+		}
+		if closed && len(at) < 3 || len(at) < 2 {
+			return
+		}
+		contours = append(contours, Contour{Points: pts, Closed: closed})
+	}
+	for i, s := range segs {
+		if !used[i] && !ends[s.from] {
+			walk(i)
+		}
+	}
+	for i := range segs {
+		if !used[i] {
+			walk(i)
+		}
+	}
+	return contours
+}
+
+// edgeKey returns the undirected edge uv with the smaller index first.
+func edgeKey(u, v int32) [2]int32 {
+	if u > v {
+		return [2]int32{v, u}
+	}
+	return [2]int32{u, v}
+}
@@ -0,0 +1,153 @@
+package robust_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// contourArea returns twice the signed area of the contour projected to
+// the xy-plane.
+func contourArea(c robust.Contour) float64 {
+	p := c.Points
+	area := 0.0
+	for i := 0; i < len(p); i += 3 {
+		j := (i + 3) % len(p)
+		area += p[i]*p[j+1] - p[j]*p[i+1]
+	}
+	return area
+}
+
+func Test_SlicePlane(t *testing.T) {
+	verts, tris := cube(nil, nil, 0, 4)
+	hollowVerts, hollowTris := cube(verts, tris, 1, 3)
+	// Turn the inner cube inside out to bound the cavity
+	for f := len(tris); f < len(hollowTris); f += 3 {
+		hollowTris[f+1], hollowTris[f+2] = hollowTris[f+2], hollowTris[f+1]
+	}
+	horizontal := func(z float64) [3][]float64 {
+		return [3][]float64{{0, 0, z}, {1, 0, z}, {0, 1, z}}
+	}
+
+	tests := []struct {
+		label  string
+		verts  []float64
+		tris   []int32
+		plane  [3][]float64
+		points []int
+		areas  []float64
+	}{
+		{"middle", verts, tris, horizontal(2), []int{8}, []float64{32}},
+		{"top", verts, tris, horizontal(4), []int{4}, []float64{32}},
+		{"bottom", verts, tris, horizontal(0), nil, nil},
+		{"above", verts, tris, horizontal(5), nil, nil},
+		{"hollow", hollowVerts, hollowTris, horizontal(2), []int{8, 8}, []float64{32, -8}},
+		{"cavity top", hollowVerts, hollowTris, horizontal(3), []int{8, 4}, []float64{32, -8}},
+		{"corner", verts, tris, [3][]float64{{4, 4, 4}, {5, 4, 3}, {4, 5, 3}}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			contours := robust.SlicePlane(tt.verts, tt.tris, tt.plane)
+			if len(contours) != len(tt.points) {
+				t.Fatalf("want: %d contours; got: %+v", len(tt.points), contours)
+			}
+			for i, c := range contours {
+				if !c.Closed || len(c.Points) != 3*tt.points[i] {
+					t.Errorf("contour %d want: %d closed points; got: %+v", i, tt.points[i], c)
+				}
+				if a := contourArea(c); a != tt.areas[i] {
+					t.Errorf("contour %d area want: %g; got: %g", i, tt.areas[i], a)
+				}
+				for k := 2; k < len(c.Points); k += 3 {
+					if c.Points[k] != tt.plane[1][2] {
+						t.Errorf("contour %d point %d off plane: %v", i, k/3, c.Points[k-2:k+1])
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_SlicePlane_Open(t *testing.T) {
+	verts := []float64{0, 0, -1, 2, 0, 1, 0, 2, 1}
+	contours := robust.SlicePlane(verts, []int32{0, 1, 2}, [3][]float64{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+	if len(contours) != 1 || contours[0].Closed {
+		t.Fatalf("want: 1 open contour; got: %+v", contours)
+	}
+	want := []float64{0, 1, 0, 1, 0, 0}
+	for i, v := range contours[0].Points {
+		if v != want[i] {
+			t.Fatalf("want: %v; got: %v", want, contours[0].Points)
+		}
+	}
+}
+
+// Test_SlicePlane_ErrorBound checks the constructed points of a tilted
+// slice against exact rational intersections.
+func Test_SlicePlane_ErrorBound(t *testing.T) {
+	verts := []float64{
+		0.1, 0.7, -3.3, 1.9, 0.3, 2.1, 0.45, 2.6, 0.9,
+		-1.7, 1.1, 0.6, 1e-3, -2.2, 1.3,
+	}
+	tris := []int32{0, 1, 2, 0, 2, 3, 0, 3, 4, 0, 4, 1, 1, 4, 3, 1, 3, 2}
+	plane := [3][]float64{{0.3, -0.1, 0.2}, {1.1, 0.5, 0.7}, {-0.4, 1.3, 0.05}}
+	contours := robust.SlicePlane(verts, tris, plane)
+	if len(contours) != 1 || !contours[0].Closed {
+		t.Fatalf("want: 1 closed contour; got: %+v", contours)
+	}
+
+	rat := func(v float64) *big.Rat { return new(big.Rat).SetFloat64(v) }
+	sub := func(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(a, b) }
+	mul := func(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(a, b) }
+	vec := func(p []float64) [3]*big.Rat { return [3]*big.Rat{rat(p[0]), rat(p[1]), rat(p[2])} }
+	diff := func(a, b [3]*big.Rat) (d [3]*big.Rat) {
+		for i := range d {
+			d[i] = sub(a[i], b[i])
+		}
+		return d
+	}
+	dot := func(a, b [3]*big.Rat) *big.Rat {
+		s := new(big.Rat)
+		for i := range a {
+			s.Add(s, mul(a[i], b[i]))
+		}
+		return s
+	}
+	p0 := vec(plane[0])
+	u, v := diff(vec(plane[1]), p0), diff(vec(plane[2]), p0)
+	n := [3]*big.Rat{
+		sub(mul(u[1], v[2]), mul(u[2], v[1])),
+		sub(mul(u[2], v[0]), mul(u[0], v[2])),
+		sub(mul(u[0], v[1]), mul(u[1], v[0])),
+	}
+
+	eps := math.Ldexp(1, -53)
+	pts := contours[0].Points
+	for i := 0; i < len(pts); i += 3 {
+		// Find the edge whose exact cut is within the bound
+		found := false
+		for f := 0; f < len(tris) && !found; f += 3 {
+			for k := 0; k < 3 && !found; k++ {
+				a, b := verts[3*tris[f+k]:][:3], verts[3*tris[f+(k+1)%3]:][:3]
+				ra, rb := vec(a), vec(b)
+				da, db := dot(n, diff(ra, p0)), dot(n, diff(rb, p0))
+				if da.Sign()*db.Sign() >= 0 {
+					continue
+				}
+				s := new(big.Rat).Quo(da, sub(da, db))
+				ok := true
+				for c := 0; c < 3; c++ {
+					x, _ := new(big.Rat).Add(ra[c], mul(s, sub(rb[c], ra[c]))).Float64()
+					bound := (math.Abs(x) + 4*math.Abs(b[c]-a[c])) * eps
+					ok = ok && math.Abs(pts[i+c]-x) <= bound
+				}
+				found = ok
+			}
+		}
+		if !found {
+			t.Errorf("point %d %v not within bound of any cut", i/3, pts[i:i+3])
+		}
+	}
+}
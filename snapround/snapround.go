@@ -0,0 +1,225 @@
+// Package snapround rounds segment arrangements to a grid with iterated
+// snap rounding, so that no new crossings appear and every vertex keeps a
+// distance of at least half a pixel from every edge it's not on.
+//
+// The grid has a point at every integer multiple of the spacing and each
+// pixel is the square of points rounding to it. A pixel is hot when it
+// contains an endpoint or a crossing of two segments. Each segment is
+// replaced by the polyline through the centers of the hot pixels it
+// passes through, and every edge of that polyline is routed the same way
+// until none passes through another hot pixel.
+//
+// Crossings are found with `sweep.Intersections` and never constructed.
+// The pixel of a crossing is located exactly with
+// `robust.ComparePointLPI2` and a segment is tested against a pixel with
+// `robust.Orient2`, so the rounding is consistent for any input.
+package snapround
+
+import (
+	"math"
+	"sort"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+	"neilpa.me/cgo-shewchuk-robust/sweep"
+)
+
+// Round snaps the segments, given as a flat `[]float64` buffer of x0, y0,
+// x1, y1 quadruples, to the grid with the spacing size. It returns a
+// polyline per segment as a flat buffer of XY grid points from its first
+// to its second endpoint. A segment within a single pixel collapses to
+// one point.
+//
+// Points are located in pixels that are closed below and open above on
+// each axis, while segments hit every pixel whose closed square they
+// meet. The grid coordinates must be representable as k·size for the
+// pixel indices k in range.
+func Round(segs []float64, size float64) [][]float64 {
+	g := &grid{size: size, hot: make(map[pixel]bool)}
+	n := len(segs) / 4
+	seg := func(i int) (a, b []float64) { return segs[4*i : 4*i+2], segs[4*i+2 : 4*i+4] }
+
+	for i := 0; i < n; i++ {
+		a, b := seg(i)
+		g.add(pixel{g.locate(0, a, nil), g.locate(1, a, nil)})
+		g.add(pixel{g.locate(0, b, nil), g.locate(1, b, nil)})
+	}
+	for _, pair := range sweep.Intersections(segs) {
+		a, b := seg(pair[0])
+		c, d := seg(pair[1])
+		o1 := robust.Orient2(a, b, c)
+		o2 := robust.Orient2(a, b, d)
+		o3 := robust.Orient2(c, d, a)
+		o4 := robust.Orient2(c, d, b)
+		if o1*o2 < 0 && o3*o4 < 0 {
+			lines := &[2][2][]float64{{a, b}, {c, d}}
+			g.add(pixel{g.locate(0, nil, lines), g.locate(1, nil, lines)})
+		}
+		// Other intersections are endpoints, which are hot already
+	}
+
+	out := make([][]float64, n)
+	for i := range out {
+		a, b := seg(i)
+		path := g.route(a, b)
+		for {
+			next := []pixel{path[0]}
+			for k := 1; k < len(path); k++ {
+				next = append(next, g.route(g.center(path[k-1]), g.center(path[k]))[1:]...)
+			}
+			if len(next) == len(path) {
+				break
+			}
+			path = next
+		}
+		for _, p := range path {
+			out[i] = append(out[i], g.center(p)...)
+		}
+	}
+	return out
+}
+
+// pixel is the integer index of a grid point along each axis.
+type pixel [2]int64
+
+type grid struct {
+	size    float64
+	hot     map[pixel]bool
+	columns map[int64][]int64
+}
+
+func (g *grid) add(p pixel) {
+	if g.hot[p] {
+		return
+	}
+	g.hot[p] = true
+	if g.columns == nil {
+		g.columns = make(map[int64][]int64)
+	}
+	g.columns[p[0]] = append(g.columns[p[0]], p[1])
+}
+
+// center returns the grid point of the pixel.
+func (g *grid) center(p pixel) []float64 {
+	return []float64{float64(p[0]) * g.size, float64(p[1]) * g.size}
+}
+
+// bound returns the lower boundary of pixel k along an axis.
+func (g *grid) bound(k int64) float64 {
+	return (float64(k) - 0.5) * g.size
+}
+
+// locate returns the pixel index along axis of the explicit point p, or
+// of the crossing of the lines when p is nil. The estimate from rounded
+// arithmetic is corrected with exact comparisons against the boundaries.
+func (g *grid) locate(axis int, p []float64, lines *[2][2][]float64) int64 {
+	// cmp returns the sign of the coordinate minus v
+	cmp := func(v float64) float64 {
+		if p != nil {
+			return p[axis] - v
+		}
+		e := []float64{v, v}
+		return -robust.ComparePointLPI2(axis, e, lines[0], lines[1])
+	}
+
+	var x float64
+	if p != nil {
+		x = p[axis]
+	} else {
+		x = crossing(lines)[axis]
+	}
+	k := int64(math.Floor(x/g.size + 0.5))
+	for cmp(g.bound(k)) < 0 {
+		k--
+	}
+	for cmp(g.bound(k+1)) >= 0 {
+		k++
+	}
+	return k
+}
+
+// route returns the hot pixels met by the segment from a to b in order,
+// starting with the pixel of a and ending with the pixel of b.
+func (g *grid) route(a, b []float64) []pixel {
+	pa := pixel{g.locate(0, a, nil), g.locate(1, a, nil)}
+	pb := pixel{g.locate(0, b, nil), g.locate(1, b, nil)}
+	lo, hi := pa[0], pb[0]
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	var hits []pixel
+	visit := func(p pixel) {
+		if p != pa && p != pb && g.meets(a, b, p) {
+			hits = append(hits, p)
+		}
+	}
+	if hi-lo < int64(len(g.columns)) {
+		for x := lo; x <= hi; x++ {
+			for _, y := range g.columns[x] {
+				visit(pixel{x, y})
+			}
+		}
+	} else {
+		for p := range g.hot {
+			visit(p)
+		}
+	}
+
+	// The pixels met by a segment form a staircase that is monotone along
+	// both axes in the direction of the segment.
+	sx, sy := int64(1), int64(1)
+	if b[0] < a[0] {
+		sx = -1
+	}
+	if b[1] < a[1] {
+		sy = -1
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i][0] != hits[j][0] {
+			return sx*hits[i][0] < sx*hits[j][0]
+		}
+		return sy*hits[i][1] < sy*hits[j][1]
+	})
+
+	path := append([]pixel{pa}, hits...)
+	if pb != pa {
+		path = append(path, pb)
+	}
+	return path
+}
+
+// meets reports whether the segment ab meets the closed square of the
+// pixel, i.e. their bounding boxes overlap and the corners aren't all
+// strictly on one side of the line.
+func (g *grid) meets(a, b []float64, p pixel) bool {
+	x0, x1 := g.bound(p[0]), g.bound(p[0]+1)
+	y0, y1 := g.bound(p[1]), g.bound(p[1]+1)
+	if math.Max(a[0], b[0]) < x0 || math.Min(a[0], b[0]) > x1 ||
+		math.Max(a[1], b[1]) < y0 || math.Min(a[1], b[1]) > y1 {
+		return false
+	}
+	var above, below bool
+	for _, c := range [4][]float64{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}} {
+		o := robust.Orient2(a, b, c)
+		above = above || o >= 0
+		below = below || o <= 0
+	}
+	return above && below
+}
+
+// crossing estimates the intersection point of the lines, which lies on
+// the first segment since they cross properly.
+func crossing(lines *[2][2][]float64) []float64 {
+	a, b := lines[0][0], lines[0][1]
+	c, d := lines[1][0], lines[1][1]
+	ux, uy := b[0]-a[0], b[1]-a[1]
+	vx, vy := d[0]-c[0], d[1]-c[1]
+	wx, wy := c[0]-a[0], c[1]-a[1]
+	t := (wx*vy - wy*vx) / (ux*vy - uy*vx)
+	if !(t >= 0) {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return []float64{a[0] + t*ux, a[1] + t*uy}
+}
@@ -0,0 +1,125 @@
+package snapround_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+	"neilpa.me/cgo-shewchuk-robust/snapround"
+)
+
+func Test_Round(t *testing.T) {
+	tests := []struct {
+		label string
+		segs  []float64
+		size  float64
+		want  [][]float64
+	}{
+		{"cross", []float64{0, 0, 4, 4, 0, 4, 4, 0}, 1, [][]float64{
+			{0, 0, 2, 2, 4, 4}, {0, 4, 2, 2, 4, 0},
+		}},
+		{"off grid", []float64{0, 0, 4, 4, 0, 3.2, 3.2, 0}, 1, [][]float64{
+			{0, 0, 2, 2, 4, 4}, {0, 3, 2, 2, 3, 0},
+		}},
+		{"near miss", []float64{0, 0, 4, 0, 1.9, 0.3, 3, 3}, 1, [][]float64{
+			{0, 0, 2, 0, 4, 0}, {2, 0, 3, 3},
+		}},
+		// Snapping the first segment to the crossing at (4, 2) moves it
+		// onto the hot corner of (6, 4), so the edge is routed again.
+		{"iterated", []float64{6.5, 6.5, 3, 0, 6, 3.5, 2.5, 1}, 1, [][]float64{
+			{7, 7, 6, 4, 4, 2, 3, 1, 3, 0}, {6, 4, 4, 2, 3, 1},
+		}},
+		{"collapsed", []float64{0.1, 0.1, 0.2, 0.3}, 1, [][]float64{{0, 0}}},
+		{"half grid", []float64{0, 0, 2, 2, 0, 2, 2, 0}, 0.5, [][]float64{
+			{0, 0, 1, 1, 2, 2}, {0, 2, 1, 1, 2, 0},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			got := snapround.Round(tt.segs, tt.size)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want: %v; got: %v", tt.want, got)
+			}
+			check(t, tt.segs, tt.size, got)
+		})
+	}
+}
+
+func Test_RoundRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []float64{1, 0.25, 0.1} {
+		for iter := 0; iter < 20; iter++ {
+			segs := make([]float64, 4*20)
+			for i := range segs {
+				segs[i] = rng.Float64() * 8
+			}
+			check(t, segs, size, snapround.Round(segs, size))
+		}
+	}
+}
+
+// check verifies that every polyline runs between the grid points nearest
+// to the segment endpoints, and that the edges only meet at shared
+// vertices or overlap between them, i.e. no edge crosses another one or
+// passes through a vertex.
+func check(t *testing.T, segs []float64, size float64, got [][]float64) {
+	t.Helper()
+	if len(got) != len(segs)/4 {
+		t.Fatalf("want %d polylines; got %d", len(segs)/4, len(got))
+	}
+	snap := func(v float64) float64 { return math.Floor(v/size+0.5) * size }
+	var edges [][2][]float64
+	var verts [][]float64
+	for i, poly := range got {
+		n := len(poly)
+		if poly[0] != snap(segs[4*i]) || poly[1] != snap(segs[4*i+1]) ||
+			poly[n-2] != snap(segs[4*i+2]) || poly[n-1] != snap(segs[4*i+3]) {
+			t.Errorf("segment %d: bad endpoints %v", i, poly)
+		}
+		for k := 0; k+1 < n; k += 2 {
+			verts = append(verts, poly[k:k+2])
+			if k+3 < n {
+				a, b := poly[k:k+2], poly[k+2:k+4]
+				if a[0] == b[0] && a[1] == b[1] {
+					t.Errorf("segment %d: repeated vertex %v", i, a)
+				}
+				edges = append(edges, [2][]float64{a, b})
+			}
+		}
+	}
+
+	for _, e := range edges {
+		for _, v := range verts {
+			if robust.Orient2(e[0], e[1], v) == 0 && strictlyBetween(e[0], e[1], v) {
+				t.Fatalf("vertex %v inside edge %v", v, e)
+			}
+		}
+	}
+	for i, e := range edges {
+		for _, f := range edges[i+1:] {
+			o1 := robust.Orient2(e[0], e[1], f[0])
+			o2 := robust.Orient2(e[0], e[1], f[1])
+			o3 := robust.Orient2(f[0], f[1], e[0])
+			o4 := robust.Orient2(f[0], f[1], e[1])
+			if o1*o2 < 0 && o3*o4 < 0 {
+				t.Fatalf("edges %v and %v cross", e, f)
+			}
+		}
+	}
+}
+
+// strictlyBetween reports whether p, collinear with ab, lies inside the
+// segment.
+func strictlyBetween(a, b, p []float64) bool {
+	if p[0] == a[0] && p[1] == a[1] || p[0] == b[0] && p[1] == b[1] {
+		return false
+	}
+	for k := 0; k < 2; k++ {
+		if p[k] < a[k] && p[k] < b[k] || p[k] > a[k] && p[k] > b[k] {
+			return false
+		}
+	}
+	return true
+}
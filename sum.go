@@ -0,0 +1,126 @@
+package robust
+
+import (
+	"math"
+	"math/big"
+)
+
+// Sum returns the sum of xs correctly rounded to the nearest float64, like
+// Python's `math.fsum`. The exact sum is accumulated as an expansion,
+// which usually stays only a few components long however many values
+// there are.
+//
+// Infinities and NaNs propagate as in a plain loop. Finite values whose
+// partial sums overflow, where `math.fsum` raises an error, are summed
+// again exactly with `math/big`, so the result is only infinite when the
+// exact sum rounds past the largest float64.
+func Sum(xs []float64) float64 {
+	var p partials
+	special := 0.0
+	for _, x := range xs {
+		if math.IsInf(x, 0) || math.IsNaN(x) {
+			special += x
+			continue
+		}
+		p.add(x)
+	}
+	if special != 0 || math.IsNaN(special) {
+		return special
+	}
+	if r, ok := p.round(); ok {
+		return r
+	}
+	return exactSum(xs, nil)
+}
+
+// Dot returns the dot product of xs and ys correctly rounded to the nearest
+// float64. Each product is split exactly into two values that are
+// accumulated like `Sum`. Products that overflow, or that are too small
+// for their rounding errors to be represented, are handled like partial
+// sums that overflow in `Sum`.
+//
+// It panics if the slices have different lengths.
+func Dot(xs, ys []float64) float64 {
+	if len(xs) != len(ys) {
+		panic("robust: Dot of slices with different lengths")
+	}
+	var p partials
+	special, tiny := 0.0, false
+	for i, x := range xs {
+		y := ys[i]
+		if math.IsInf(x, 0) || math.IsNaN(x) || math.IsInf(y, 0) || math.IsNaN(y) {
+			special += x * y
+			continue
+		}
+		hi, lo := twoProduct(x, y)
+		if math.Abs(hi) < tinyProduct && x != 0 && y != 0 {
+			tiny = true
+		}
+		p.add(lo)
+		p.add(hi)
+	}
+	if special != 0 || math.IsNaN(special) {
+		return special
+	}
+	if r, ok := p.round(); ok && !tiny {
+		return r
+	}
+	return exactSum(xs, ys)
+}
+
+// tinyProduct is 2^-969, below which the rounding error of a product may
+// be smaller than the smallest subnormal.
+const tinyProduct = math.SmallestNonzeroFloat64 * (1 << 105)
+
+// exactSum is the fallback of `Sum`, and of `Dot` when ys isn't nil, for
+// the finite values whose expansions overflow or whose products underflow.
+// Products of float64 values span 2^-2148 to 2^2048, so at this precision
+// every partial sum is exact and only the conversion back rounds.
+func exactSum(xs, ys []float64) float64 {
+	const prec = 4400
+	sum := new(big.Float).SetPrec(prec)
+	t := new(big.Float).SetPrec(prec)
+	u := new(big.Float).SetPrec(prec)
+	for i, x := range xs {
+		t.SetFloat64(x)
+		if ys != nil {
+			t.Mul(t, u.SetFloat64(ys[i]))
+		}
+		sum.Add(sum, t)
+	}
+	r, _ := sum.Float64()
+	return r
+}
+
+// partials is an expansion grown in place, as `growExpansion` does with
+// a new slice.
+type partials []float64
+
+func (p *partials) add(x float64) {
+	h := *p
+	i := 0
+	for _, y := range h {
+		var lo float64
+		x, lo = twoSum(x, y)
+		if lo != 0 {
+			h[i] = lo
+			i++
+		}
+	}
+	h = h[:i]
+	if x != 0 || i == 0 {
+		h = append(h, x)
+	}
+	*p = h
+}
+
+// round returns the sum correctly rounded, and false if the expansion
+// overflowed. Sums rounded to the largest float64 are redone too, since
+// roundExpansion can't round past it to infinity.
+func (p partials) round() (float64, bool) {
+	if len(p) == 0 {
+		return 0, true
+	}
+	r := roundExpansion(p)
+	return r, math.Abs(r) < math.MaxFloat64
+}
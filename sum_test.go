@@ -0,0 +1,183 @@
+package robust_test
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// illConditioned returns n values spanning many magnitudes where most of
+// the large ones cancel, so a plain loop loses all the small ones.
+func illConditioned(rng *rand.Rand, n int) []float64 {
+	xs := make([]float64, 0, n)
+	for len(xs) < n {
+		x := math.Ldexp(rng.Float64()-0.5, rng.Intn(200)-100)
+		xs = append(xs, x)
+		if rng.Intn(2) == 0 {
+			xs = append(xs, -x*(1+math.Ldexp(float64(rng.Intn(3)-1), -52)))
+		}
+	}
+	rng.Shuffle(len(xs), func(i, j int) { xs[i], xs[j] = xs[j], xs[i] })
+	return xs[:n]
+}
+
+func Test_Sum(t *testing.T) {
+	half := math.Ldexp(1, -53)
+	for _, tt := range []struct {
+		label string
+		xs    []float64
+		want  float64
+	}{
+		{"empty", nil, 0},
+		{"zeros", []float64{0, math.Copysign(0, -1)}, 0},
+		{"cancel", []float64{1e100, 1, -1e100}, 1},
+		{"tie even", []float64{1, half}, 1},
+		{"tie up", []float64{1, half, math.Ldexp(1, -100)}, 1 + 2*half},
+		{"tie down", []float64{1, half, -math.Ldexp(1, -100)}, 1},
+		{"inf", []float64{1, math.Inf(1), 2}, math.Inf(1)},
+		{"inf nan", []float64{math.Inf(1), math.Inf(-1)}, math.NaN()},
+		{"nan", []float64{1, math.NaN()}, math.NaN()},
+		{"overflow", []float64{1e308, 1e308, -1e308}, 1e308},
+		{"overflow cancel", []float64{1e308, 1e308, -1e308, -1e308, 1e-320}, 1e-320},
+		{"overflow inf", []float64{1e308, 1e308}, math.Inf(1)},
+		{"max", []float64{math.MaxFloat64, math.Ldexp(1, 969)}, math.MaxFloat64},
+		{"max tie", []float64{math.MaxFloat64, math.Ldexp(1, 970)}, math.Inf(1)},
+		{"max tie down", []float64{math.MaxFloat64, math.Ldexp(1, 970), -math.Ldexp(1, -1074)}, math.MaxFloat64},
+	} {
+		t.Run(tt.label, func(t *testing.T) {
+			got := robust.Sum(tt.xs)
+			if got != tt.want && !(math.IsNaN(got) && math.IsNaN(tt.want)) {
+				t.Errorf("want %g; got %g", tt.want, got)
+			}
+		})
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		xs := illConditioned(rng, 1+rng.Intn(100))
+		if i%2 == 1 {
+			// Large enough for partial sums to overflow
+			for j := range xs {
+				xs[j] = math.Ldexp(xs[j], 924)
+			}
+		}
+		sum := new(big.Rat)
+		for _, x := range xs {
+			sum.Add(sum, new(big.Rat).SetFloat64(x))
+		}
+		want, _ := sum.Float64()
+		if got := robust.Sum(xs); math.Float64bits(got) != math.Float64bits(want) {
+			t.Fatalf("case %d: want %g; got %g", i, want, got)
+		}
+	}
+}
+
+// Test_Dot runs with both ways of computing exact products, which is the
+// same before Go 1.14.
+func Test_Dot(t *testing.T) {
+	for _, fma := range []bool{false, true} {
+		restore := robust.UseFMAProducts(fma)
+		testDot(t)
+		restore()
+	}
+}
+
+func testDot(t *testing.T) {
+	for _, tt := range []struct {
+		label  string
+		xs, ys []float64
+		want   float64
+	}{
+		{"cancel", []float64{1e100, 3, -1e100}, []float64{1e100, 1e-100, 1e100}, 3e-100},
+		{"inf", []float64{1, math.Inf(1)}, []float64{2, 0}, math.NaN()},
+		{"large", []float64{1e305}, []float64{1}, 1e305},
+		{"max", []float64{math.MaxFloat64, 1}, []float64{1 - math.Ldexp(1, -53), 1}, math.MaxFloat64 * (1 - math.Ldexp(1, -53))},
+		{"overflow", []float64{1e300, 1e300}, []float64{10, -9}, 1e300},
+		{"overflow cancel", []float64{1e200, 1e200}, []float64{1e200, -1e200}, 0},
+		{"overflow inf", []float64{1e200}, []float64{1e200}, math.Inf(1)},
+	} {
+		got := robust.Dot(tt.xs, tt.ys)
+		if got != tt.want && !(math.IsNaN(got) && math.IsNaN(tt.want)) {
+			t.Errorf("%s: want %g; got %g", tt.label, tt.want, got)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		xs := illConditioned(rng, 1+rng.Intn(100))
+		ys := illConditioned(rng, len(xs))
+		if i%2 == 1 {
+			// Large enough to split scaled and for products to overflow
+			for j := range xs {
+				xs[j], ys[j] = math.Ldexp(xs[j], 460), math.Ldexp(ys[j], 460)
+			}
+		}
+		want := exactDot(xs, ys)
+		if got := robust.Dot(xs, ys); math.Float64bits(got) != math.Float64bits(want) {
+			t.Fatalf("case %d: want %g; got %g", i, want, got)
+		}
+	}
+
+	// Tiny products whose leading parts cancel, leaving their rounding
+	// errors, which may be subnormal, to decide the result.
+	subnormal := 0
+	for i := 0; i < 2000; i++ {
+		e := -540 + rng.Intn(40)
+		x0, y0, x1 := math.Ldexp(1+rng.Float64(), e), math.Ldexp(1+rng.Float64(), e), math.Ldexp(1+rng.Float64(), e)
+		xs := []float64{x0, x1, math.Ldexp(rng.NormFloat64(), e-10), math.Ldexp(rng.NormFloat64(), e-20)}
+		ys := []float64{y0, -x0 * y0 / x1, math.Ldexp(rng.NormFloat64(), e-40), math.Ldexp(rng.NormFloat64(), e-30)}
+		want := exactDot(xs, ys)
+		if got := robust.Dot(xs, ys); math.Float64bits(got) != math.Float64bits(want) {
+			t.Fatalf("tiny case %d: want %g; got %g", i, want, got)
+		}
+		if want != 0 && math.Abs(want) < math.SmallestNonzeroFloat64*(1<<52) {
+			subnormal++
+		}
+	}
+	if subnormal == 0 {
+		t.Error("no subnormal results")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("want panic for different lengths")
+		}
+	}()
+	robust.Dot([]float64{1}, []float64{1, 2})
+}
+
+// exactDot returns the dot product of xs and ys rounded from exact
+// rational arithmetic.
+func exactDot(xs, ys []float64) float64 {
+	sum := new(big.Rat)
+	for j := range xs {
+		sum.Add(sum, new(big.Rat).Mul(new(big.Rat).SetFloat64(xs[j]), new(big.Rat).SetFloat64(ys[j])))
+	}
+	want, _ := sum.Float64()
+	return want
+}
+
+func Benchmark_Sum(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	xs := make([]float64, 1000)
+	for i := range xs {
+		xs[i] = rng.NormFloat64()
+	}
+	b.Run("loop", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			sum := 0.0
+			for _, x := range xs {
+				sum += x
+			}
+			result = sum
+		}
+	})
+	b.Run("sum", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			result = robust.Sum(xs)
+		}
+	})
+}
@@ -0,0 +1,226 @@
+// Package sweep reports all intersecting pairs among a set of segments
+// with a Bentley–Ottmann sweep.
+//
+// Floating-point versions of the sweep break when an intersection point
+// is rounded: events get processed out of order and the status structure
+// stops being sorted. Here crossings are never constructed. An event is
+// either an input endpoint or the crossing of two segments kept as that
+// pair, events are ordered with `robust.CompareLPI2` and
+// `robust.ComparePointLPI2`, and segments are ordered along the sweep line
+// relative to the current event with `robust.Orient2` and
+// `robust.Orient2LPI`. Vertical, overlapping and degenerate segments and
+// any number of segments through one point are handled exactly.
+package sweep
+
+import (
+	"container/heap"
+	"sort"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+	"neilpa.me/cgo-shewchuk-robust/internal/sign"
+)
+
+// Intersections returns every pair of segments (i < j) that share at
+// least one point, sorted. Segments are given as a flat `[]float64`
+// buffer of x0, y0, x1, y1 quadruples.
+//
+// The sweep visits the events in lexicographic (x, then y) order and
+// takes O((n + k) log n) predicate evaluations for n segments with k
+// intersection events, plus one report per pair meeting at each event.
+func Intersections(segs []float64) [][2]int {
+	s := &sweeper{found: make(map[[2]int]bool)}
+	s.queue.s = s
+	for i := 0; i+3 < len(segs); i += 4 {
+		a, b := segs[i:i+2], segs[i+2:i+4]
+		if lexLess(b, a) {
+			a, b = b, a
+		}
+		id := len(s.segs)
+		s.segs = append(s.segs, segment{left: a, right: b})
+		heap.Push(&s.queue, event{p: a, starts: []int{id}})
+		heap.Push(&s.queue, event{p: b})
+	}
+
+	for s.queue.Len() > 0 {
+		e := heap.Pop(&s.queue).(event)
+		for s.queue.Len() > 0 && s.compare(e, s.queue.events[0]) == 0 {
+			e.starts = append(e.starts, heap.Pop(&s.queue).(event).starts...)
+		}
+		s.handle(e)
+	}
+
+	pairs := make([][2]int, 0, len(s.found))
+	for p := range s.found {
+		pairs = append(pairs, p)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	return pairs
+}
+
+// segment is an input segment with its endpoints in lexicographic order.
+type segment struct {
+	left, right []float64
+}
+
+func (s *segment) line() [2][]float64 {
+	return [2][]float64{s.left, s.right}
+}
+
+// event is a point of the sweep, either the explicit point p or the
+// crossing of the segments s and t, along with the segments starting at
+// it.
+type event struct {
+	p      []float64
+	s, t   int
+	starts []int
+}
+
+type sweeper struct {
+	segs   []segment
+	queue  queue
+	status []int
+	found  map[[2]int]bool
+}
+
+// handle processes the segments through the event point: the ones ending
+// or passing through it form a contiguous run of the status, which is
+// replaced by the ones continuing or starting there in their order just
+// after it.
+func (s *sweeper) handle(e event) {
+	lo := sort.Search(len(s.status), func(i int) bool { return s.side(s.status[i], e) <= 0 })
+	hi := sort.Search(len(s.status), func(i int) bool { return s.side(s.status[i], e) < 0 })
+
+	through := append(append([]int(nil), s.status[lo:hi]...), e.starts...)
+	for i := range through {
+		for j := i + 1; j < len(through); j++ {
+			a, b := through[i], through[j]
+			if a > b {
+				a, b = b, a
+			}
+			s.found[[2]int{a, b}] = true
+		}
+	}
+
+	var next []int
+	for _, id := range through {
+		if !s.ends(id, e) {
+			next = append(next, id)
+		}
+	}
+	sort.Slice(next, func(i, j int) bool { return s.below(next[i], next[j], e) })
+
+	status := append([]int(nil), s.status[:lo]...)
+	status = append(status, next...)
+	s.status = append(status, s.status[hi:]...)
+
+	if len(next) == 0 {
+		if lo > 0 && lo < len(s.status) {
+			s.check(s.status[lo-1], s.status[lo], e)
+		}
+		return
+	}
+	if lo > 0 {
+		s.check(s.status[lo-1], s.status[lo], e)
+	}
+	if k := lo + len(next); k < len(s.status) {
+		s.check(s.status[k-1], s.status[k], e)
+	}
+}
+
+// check schedules the crossing of the neighbors a and b if it lies after
+// the event. Touching and overlapping segments meet at an endpoint of one
+// of them, which is an event already.
+func (s *sweeper) check(a, b int, e event) {
+	sa, sb := &s.segs[a], &s.segs[b]
+	o1 := sign.Of(robust.Orient2(sa.left, sa.right, sb.left))
+	o2 := sign.Of(robust.Orient2(sa.left, sa.right, sb.right))
+	o3 := sign.Of(robust.Orient2(sb.left, sb.right, sa.left))
+	o4 := sign.Of(robust.Orient2(sb.left, sb.right, sa.right))
+	if o1*o2 >= 0 || o3*o4 >= 0 {
+		return
+	}
+	x := event{s: a, t: b}
+	if s.compare(x, e) > 0 {
+		heap.Push(&s.queue, x)
+	}
+}
+
+// side returns the sign of `robust.Orient2` of the segment and the event
+// point, which is positive when the point is above the segment and zero
+// when it's on it.
+func (s *sweeper) side(id int, e event) int {
+	seg := &s.segs[id]
+	if e.p != nil {
+		return sign.Of(robust.Orient2(seg.left, seg.right, e.p))
+	}
+	return sign.Of(robust.Orient2LPI(seg.left, seg.right, s.segs[e.s].line(), s.segs[e.t].line()))
+}
+
+// ends reports whether the segment ends at the event point.
+func (s *sweeper) ends(id int, e event) bool {
+	return s.compare(event{p: s.segs[id].right}, e) == 0
+}
+
+// below reports whether segment a is below segment b just after the event
+// point, which both contain. Their right endpoints lie after the point,
+// in a half plane, so the orientation orders their directions. Vertical
+// segments come last and overlapping ones by index.
+func (s *sweeper) below(a, b int, e event) bool {
+	ra, rb := s.segs[a].right, s.segs[b].right
+	var o int
+	if e.p != nil {
+		o = sign.Of(robust.Orient2(e.p, ra, rb))
+	} else {
+		o = sign.Of(robust.Orient2LPI(ra, rb, s.segs[e.s].line(), s.segs[e.t].line()))
+	}
+	if o == 0 {
+		return a < b
+	}
+	return o > 0
+}
+
+// compare orders event points lexicographically.
+func (s *sweeper) compare(x, y event) int {
+	for axis := 0; axis < 2; axis++ {
+		var c float64
+		switch {
+		case x.p != nil && y.p != nil:
+			c = x.p[axis] - y.p[axis]
+		case x.p != nil:
+			c = robust.ComparePointLPI2(axis, x.p, s.segs[y.s].line(), s.segs[y.t].line())
+		case y.p != nil:
+			c = -robust.ComparePointLPI2(axis, y.p, s.segs[x.s].line(), s.segs[x.t].line())
+		default:
+			c = robust.CompareLPI2(axis, s.segs[x.s].line(), s.segs[x.t].line(), s.segs[y.s].line(), s.segs[y.t].line())
+		}
+		if c != 0 {
+			return sign.Of(c)
+		}
+	}
+	return 0
+}
+
+// queue is a `container/heap` of events ordered by the sweeper.
+type queue struct {
+	events []event
+	s      *sweeper
+}
+
+func (q *queue) Len() int           { return len(q.events) }
+func (q *queue) Less(i, j int) bool { return q.s.compare(q.events[i], q.events[j]) < 0 }
+func (q *queue) Swap(i, j int)      { q.events[i], q.events[j] = q.events[j], q.events[i] }
+func (q *queue) Push(x interface{}) { q.events = append(q.events, x.(event)) }
+func (q *queue) Pop() interface{} {
+	e := q.events[len(q.events)-1]
+	q.events = q.events[:len(q.events)-1]
+	return e
+}
+
+func lexLess(p, q []float64) bool {
+	return p[0] < q[0] || p[0] == q[0] && p[1] < q[1]
+}
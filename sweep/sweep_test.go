@@ -0,0 +1,95 @@
+package sweep_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+	"neilpa.me/cgo-shewchuk-robust/sweep"
+)
+
+// bruteForce tests every pair of segments exactly.
+func bruteForce(segs []float64) [][2]int {
+	n := len(segs) / 4
+	var pairs [][2]int
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if intersect(segs[4*i:4*i+4], segs[4*j:4*j+4]) {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	return pairs
+}
+
+func intersect(s, t []float64) bool {
+	a, b, c, d := s[0:2], s[2:4], t[0:2], t[2:4]
+	o1 := robust.Orient2(a, b, c)
+	o2 := robust.Orient2(a, b, d)
+	o3 := robust.Orient2(c, d, a)
+	o4 := robust.Orient2(c, d, b)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	return o1 == 0 && between(a, b, c) || o2 == 0 && between(a, b, d) ||
+		o3 == 0 && between(c, d, a) || o4 == 0 && between(c, d, b)
+}
+
+// between reports whether p, collinear with ab, lies on the segment.
+func between(a, b, p []float64) bool {
+	for k := 0; k < 2; k++ {
+		if p[k] < a[k] && p[k] < b[k] || p[k] > a[k] && p[k] > b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func Test_Intersections(t *testing.T) {
+	tests := []struct {
+		label string
+		segs  []float64
+		want  [][2]int
+	}{
+		{"cross", []float64{0, 0, 2, 2, 0, 2, 2, 0}, [][2]int{{0, 1}}},
+		{"disjoint", []float64{0, 0, 1, 0, 0, 1, 1, 1}, nil},
+		{"touching", []float64{0, 0, 2, 0, 1, 0, 1, 1}, [][2]int{{0, 1}}},
+		{"shared endpoint", []float64{0, 0, 1, 1, 1, 1, 2, 0}, [][2]int{{0, 1}}},
+		{"overlap", []float64{0, 0, 2, 0, 1, 0, 3, 0, 2.5, 0, 4, 0}, [][2]int{{0, 1}, {1, 2}}},
+		{"vertical", []float64{1, -1, 1, 1, 0, 0, 2, 0, 0, 2, 2, 2}, [][2]int{{0, 1}}},
+		{"concurrent", []float64{
+			0, 0, 3, 3, 0, 3, 3, 0, 0, 1.5, 3, 1.5, 1.5, 0, 1.5, 3, 0, 1, 3, 2,
+		}, [][2]int{{0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}}},
+		{"point", []float64{1, 1, 1, 1, 0, 0, 2, 2}, [][2]int{{0, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			got := sweep.Intersections(tt.segs)
+			if len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want: %v; got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_IntersectionsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	grid := func() float64 { return float64(rng.Intn(8)) }
+	// Crossings near 1/3 can't be represented, so rounding them would
+	// misorder events.
+	third := func() float64 { return 1.0/3 + float64(rng.Intn(5)-2)*1e-16 }
+	uniform := func() float64 { return rng.Float64() }
+	for _, gen := range []func() float64{grid, third, uniform} {
+		for i := 0; i < 100; i++ {
+			segs := make([]float64, 4*(2+rng.Intn(30)))
+			for k := range segs {
+				segs[k] = gen()
+			}
+			want, got := bruteForce(segs), sweep.Intersections(segs)
+			if len(got) != len(want) || len(got) > 0 && !reflect.DeepEqual(got, want) {
+				t.Fatalf("%v\nwant: %v\ngot:  %v", segs, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package robust
+
+import "neilpa.me/cgo-shewchuk-robust/internal/sign"
+
+// TriTriResult classifies the intersection of two triangles in 3D.
+type TriTriResult int
+
+const (
+	// TriTriDisjoint means the triangles have no point in common.
+	TriTriDisjoint TriTriResult = iota
+	// TriTriTouching means the triangles intersect but their relative
+	// interiors don't, e.g. a shared edge or vertex, or a vertex or edge
+	// of one resting on the other.
+	TriTriTouching
+	// TriTriCoplanar means the triangles are coplanar and their
+	// interiors overlap in a region of positive area.
+	TriTriCoplanar
+	// TriTriProper means the triangles are not coplanar and their
+	// interiors cross along a segment of positive length.
+	TriTriProper
+)
+
+func (r TriTriResult) String() string {
+	switch r {
+	case TriTriDisjoint:
+		return "disjoint"
+	case TriTriTouching:
+		return "touching"
+	case TriTriCoplanar:
+		return "coplanar"
+	case TriTriProper:
+		return "proper"
+	}
+	return "unknown"
+}
+
+// TriTriIntersect3 exactly classifies the intersection of the triangles
+// a and b. Every decision is made from the signs of `Orient3`, or of
+// `Orient2` on a coordinate projection for coplanar triangles, so shared
+// edges and vertices are never mistaken for crossings.
+//
+// Both triangles must be non-degenerate, i.e. their vertices must not be
+// collinear. Each slice parameter must contain at least 3 values.
+func TriTriIntersect3(a0, a1, a2, b0, b1, b2 []float64) TriTriResult {
+	a := [3][]float64{a0, a1, a2}
+	b := [3][]float64{b0, b1, b2}
+
+	// Side of each vertex relative to the other triangle's plane, where
+	// positive is the side the normal (v1-v0)×(v2-v0) points to.
+	var sa, sb [3]int
+	for i := 0; i < 3; i++ {
+		sa[i] = -sign.Of(Orient3(b0, b1, b2, a[i]))
+		sb[i] = -sign.Of(Orient3(a0, a1, a2, b[i]))
+	}
+	if oneSided(sa) || oneSided(sb) {
+		return TriTriDisjoint
+	}
+	if sa == [3]int{} {
+		return triTriCoplanar(a, b)
+	}
+
+	// Each triangle meets the other's plane in a segment (or point) on
+	// the common line L. Its endpoints are given by vertex pairs (x, y)
+	// with s[x] <= 0 <= s[y], standing for the point where xy meets the
+	// plane. For such pairs from a and b, sign.Of(Orient3(x, y, u, v)) is
+	// the order of the two points along L.
+	cutsA, strictA := planeCuts(sa)
+	cutsB, strictB := planeCuts(sb)
+	var below, above int
+	for _, ea := range cutsA {
+		for _, eb := range cutsB {
+			switch sign.Of(Orient3(a[ea[0]], a[ea[1]], b[eb[0]], b[eb[1]])) {
+			case -1:
+				below++
+			case 1:
+				above++
+			}
+		}
+	}
+	n := len(cutsA) * len(cutsB)
+	switch {
+	case below == n || above == n:
+		return TriTriDisjoint
+	case below > 0 && above > 0 && strictA && strictB:
+		return TriTriProper
+	}
+	return TriTriTouching
+}
+
+// triTriCoplanar classifies coplanar triangles on the projection that
+// drops an axis along which their common normal is non-zero.
+//
+// By the separating axis theorem for convex polygons, the triangles are
+// disjoint iff the line through some edge strictly separates them, and
+// their interiors are disjoint iff such a line weakly separates them.
+func triTriCoplanar(a, b [3][]float64) TriTriResult {
+	var pa, pb [3][]float64
+	for axis := 2; axis >= 0; axis-- {
+		i, j := (axis+1)%3, (axis+2)%3
+		for k := 0; k < 3; k++ {
+			pa[k] = []float64{a[k][i], a[k][j]}
+			pb[k] = []float64{b[k][i], b[k][j]}
+		}
+		if Orient2(pa[0], pa[1], pa[2]) != 0 {
+			break
+		}
+	}
+	ccw(&pa)
+	ccw(&pb)
+
+	touching := false
+	for _, tris := range [2][2][3][]float64{{pa, pb}, {pb, pa}} {
+		t, o := tris[0], tris[1]
+		for i := 0; i < 3; i++ {
+			e0, e1 := t[i], t[(i+1)%3]
+			var outside, on int
+			for _, p := range o {
+				switch sign.Of(Orient2(e0, e1, p)) {
+				case -1:
+					outside++
+				case 0:
+					on++
+				}
+			}
+			if outside == 3 {
+				return TriTriDisjoint
+			}
+			if outside+on == 3 {
+				touching = true
+			}
+		}
+	}
+	if touching {
+		return TriTriTouching
+	}
+	return TriTriCoplanar
+}
+
+// planeCuts returns the vertex pairs (x, y) with s[x] <= 0 <= s[y] and
+// s[x] != s[y] whose points on the plane bound the triangle's intersection
+// with it. Strict is true when the triangle has vertices on both sides,
+// so that the open intersection segment lies in the triangle's interior.
+func planeCuts(s [3]int) (cuts [][2]int, strict bool) {
+	pos, neg := -1, -1
+	for i, v := range s {
+		if v > 0 {
+			pos = i
+		} else if v < 0 {
+			neg = i
+		}
+	}
+	strict = pos >= 0 && neg >= 0
+	for i := 0; i < 3; i++ {
+		j := (i + 1) % 3
+		switch {
+		case s[i] == 0 && pos >= 0:
+			cuts = append(cuts, [2]int{i, pos})
+		case s[i] == 0:
+			cuts = append(cuts, [2]int{neg, i})
+		case s[i] < 0 && s[j] > 0:
+			cuts = append(cuts, [2]int{i, j})
+		case s[i] > 0 && s[j] < 0:
+			cuts = append(cuts, [2]int{j, i})
+		}
+	}
+	return cuts, strict
+}
+
+// oneSided reports whether all the signs are equal and non-zero.
+func oneSided(s [3]int) bool {
+	return s[0] != 0 && s[0] == s[1] && s[1] == s[2]
+}
+
+// ccw reorders a projected triangle to be counterclockwise.
+func ccw(t *[3][]float64) {
+	if Orient2(t[0], t[1], t[2]) < 0 {
+		t[1], t[2] = t[2], t[1]
+	}
+}
@@ -0,0 +1,49 @@
+package robust_test
+
+import (
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+func Test_TriTriIntersect3(t *testing.T) {
+	fixtures := loadCases(t, "tritri.txt", 18)
+	for _, tt := range fixtures {
+		t.Run(tt.label, func(t *testing.T) {
+			p := points(tt.args, 3)
+			want := robust.TriTriResult(tt.sign)
+
+			// The classification is symmetric and independent of the
+			// order and orientation of either triangle.
+			perms := [][6]int{
+				{0, 1, 2, 3, 4, 5},
+				{3, 4, 5, 0, 1, 2},
+				{1, 2, 0, 5, 4, 3},
+				{2, 1, 0, 4, 3, 5},
+			}
+			for _, i := range perms {
+				got := robust.TriTriIntersect3(p[i[0]], p[i[1]], p[i[2]], p[i[3]], p[i[4]], p[i[5]])
+				if got != want {
+					t.Errorf("%v: want: %v; got: %v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func Benchmark_TriTriIntersect3(b *testing.B) {
+	fixtures := loadCases(b, "tritri.txt", 18)
+	tests := make([][][]float64, len(fixtures))
+	for i, tt := range fixtures {
+		tests[i] = points(tt.args, 3)
+	}
+
+	b.ResetTimer()
+	var res robust.TriTriResult
+	for n := 0; n < b.N; n++ {
+		for _, p := range tests {
+			res = robust.TriTriIntersect3(p[0], p[1], p[2], p[3], p[4], p[5])
+		}
+	}
+	result = float64(res)
+}
@@ -0,0 +1,108 @@
+package robust
+
+// The value predicates evaluate the determinants of `Orient2`, `Orient3`,
+// `InCircle` and `InSphere` exactly with the expansion arithmetic, rather
+// than stopping once the sign is certain. Overflow and underflow in the
+// intermediate products aren't handled, as in the predicates themselves.
+
+// Orient2Value returns twice the signed area of the triangle a, b, c,
+// following the conventions of `Orient2`, correctly rounded to the nearest
+// float64.
+//
+// Each slice parameter must contain at least 2 values.
+func Orient2Value(a, b, c []float64) float64 {
+	return roundExpansion(Orient2Expansion(a, b, c))
+}
+
+// Orient2Expansion returns the determinant of `Orient2Value` as an exact
+// expansion, a sum of non-overlapping components sorted by increasing
+// magnitude. Zero is the single component 0.
+func Orient2Expansion(a, b, c []float64) []float64 {
+	acx, acy := diffExpansion(a[0], c[0]), diffExpansion(a[1], c[1])
+	bcx, bcy := diffExpansion(b[0], c[0]), diffExpansion(b[1], c[1])
+	return crossExpansion2(acx, acy, bcx, bcy)
+}
+
+// Orient3Value returns six times the signed volume of the tetrahedron a,
+// b, c, d, following the conventions of `Orient3`, correctly rounded to
+// the nearest float64.
+//
+// Each slice parameter must contain at least 3 values.
+func Orient3Value(a, b, c, d []float64) float64 {
+	return roundExpansion(Orient3Expansion(a, b, c, d))
+}
+
+// Orient3Expansion returns the determinant of `Orient3Value` as an exact
+// expansion like `Orient2Expansion`.
+func Orient3Expansion(a, b, c, d []float64) []float64 {
+	return dotExpansion(diffVector(a, d), crossExpansion(diffVector(b, d), diffVector(c, d)))
+}
+
+// InCircleValue returns the determinant whose sign is given by `InCircle`,
+// correctly rounded to the nearest float64.
+//
+// Each slice parameter must contain at least 2 values.
+func InCircleValue(a, b, c, d []float64) float64 {
+	return roundExpansion(InCircleExpansion(a, b, c, d))
+}
+
+// InCircleExpansion returns the determinant of `InCircleValue` as an exact
+// expansion like `Orient2Expansion`.
+func InCircleExpansion(a, b, c, d []float64) []float64 {
+	adx, ady := diffExpansion(a[0], d[0]), diffExpansion(a[1], d[1])
+	bdx, bdy := diffExpansion(b[0], d[0]), diffExpansion(b[1], d[1])
+	cdx, cdy := diffExpansion(c[0], d[0]), diffExpansion(c[1], d[1])
+
+	det := mulExpansion(liftExpansion(adx, ady), crossExpansion2(bdx, bdy, cdx, cdy))
+	det = sumExpansion(det, mulExpansion(liftExpansion(bdx, bdy), crossExpansion2(cdx, cdy, adx, ady)))
+	return sumExpansion(det, mulExpansion(liftExpansion(cdx, cdy), crossExpansion2(adx, ady, bdx, bdy)))
+}
+
+// InSphereValue returns the determinant whose sign is given by `InSphere`,
+// correctly rounded to the nearest float64.
+//
+// Each slice parameter must contain at least 3 values.
+func InSphereValue(a, b, c, d, e []float64) float64 {
+	return roundExpansion(InSphereExpansion(a, b, c, d, e))
+}
+
+// InSphereExpansion returns the determinant of `InSphereValue` as an exact
+// expansion like `Orient2Expansion`.
+func InSphereExpansion(a, b, c, d, e []float64) []float64 {
+	ae, be, ce, de := diffVector(a, e), diffVector(b, e), diffVector(c, e), diffVector(d, e)
+
+	// The same expansion by minors as `inSphereA`.
+	ab := crossExpansion2(ae[0], ae[1], be[0], be[1])
+	bc := crossExpansion2(be[0], be[1], ce[0], ce[1])
+	cd := crossExpansion2(ce[0], ce[1], de[0], de[1])
+	da := crossExpansion2(de[0], de[1], ae[0], ae[1])
+	ac := crossExpansion2(ae[0], ae[1], ce[0], ce[1])
+	bd := crossExpansion2(be[0], be[1], de[0], de[1])
+
+	abc := sumExpansion(subExpansion(mulExpansion(ae[2], bc), mulExpansion(be[2], ac)), mulExpansion(ce[2], ab))
+	bcd := sumExpansion(subExpansion(mulExpansion(be[2], cd), mulExpansion(ce[2], bd)), mulExpansion(de[2], bc))
+	cda := sumExpansion(sumExpansion(mulExpansion(ce[2], da), mulExpansion(de[2], ac)), mulExpansion(ae[2], cd))
+	dab := sumExpansion(sumExpansion(mulExpansion(de[2], ab), mulExpansion(ae[2], bd)), mulExpansion(be[2], da))
+
+	alift := liftExpansion(ae[0], ae[1], ae[2])
+	blift := liftExpansion(be[0], be[1], be[2])
+	clift := liftExpansion(ce[0], ce[1], ce[2])
+	dlift := liftExpansion(de[0], de[1], de[2])
+
+	det := subExpansion(mulExpansion(dlift, abc), mulExpansion(clift, dab))
+	return sumExpansion(det, subExpansion(mulExpansion(blift, cda), mulExpansion(alift, bcd)))
+}
+
+// crossExpansion2 is the exact 2D cross product ux*vy - uy*vx.
+func crossExpansion2(ux, uy, vx, vy []float64) []float64 {
+	return subExpansion(mulExpansion(ux, vy), mulExpansion(uy, vx))
+}
+
+// liftExpansion is the exact squared length of the vector v.
+func liftExpansion(v ...[]float64) []float64 {
+	sum := mulExpansion(v[0], v[0])
+	for _, x := range v[1:] {
+		sum = sumExpansion(sum, mulExpansion(x, x))
+	}
+	return sum
+}
@@ -0,0 +1,153 @@
+package robust_test
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+// ratDet is the exact determinant of the points p relative to q, with the
+// squared distances as the last column when lift is set.
+func ratDet(q []float64, lift bool, p ...[]float64) *big.Rat {
+	m := make([][]*big.Rat, len(p))
+	for i, pi := range p {
+		sq := new(big.Rat)
+		for k := range q {
+			d := new(big.Rat).Sub(new(big.Rat).SetFloat64(pi[k]), new(big.Rat).SetFloat64(q[k]))
+			m[i] = append(m[i], d)
+			sq.Add(sq, new(big.Rat).Mul(d, d))
+		}
+		if lift {
+			m[i] = append(m[i], sq)
+		}
+	}
+	return ratDeterminant(m)
+}
+
+// ratDeterminant is the Laplace expansion along the first row.
+func ratDeterminant(m [][]*big.Rat) *big.Rat {
+	if len(m) == 1 {
+		return m[0][0]
+	}
+	sum := new(big.Rat)
+	for j := range m {
+		minor := make([][]*big.Rat, 0, len(m)-1)
+		for _, row := range m[1:] {
+			r := append(append([]*big.Rat{}, row[:j]...), row[j+1:]...)
+			minor = append(minor, r)
+		}
+		term := new(big.Rat).Mul(m[0][j], ratDeterminant(minor))
+		if j%2 == 1 {
+			term.Neg(term)
+		}
+		sum.Add(sum, term)
+	}
+	return sum
+}
+
+// valueCases returns random points with full mantissas, and nearly
+// degenerate ones on a small grid, in dim dimensions and groups of n.
+func valueCases(rng *rand.Rand, dim, n int) [][][]float64 {
+	var cases [][][]float64
+	verts, idx := nearlyDegenerate(rng, 16, 1000*n)
+	for i := 0; i < len(idx); i += n {
+		var pts [][]float64
+		for _, v := range idx[i : i+n] {
+			pts = append(pts, verts[3*int(v):3*int(v)+dim])
+		}
+		cases = append(cases, pts)
+	}
+	for i := 0; i < 1000; i++ {
+		var pts [][]float64
+		for j := 0; j < n; j++ {
+			p := make([]float64, dim)
+			for k := range p {
+				p[k] = math.Ldexp(rng.Float64()-0.5, rng.Intn(20))
+			}
+			pts = append(pts, p)
+		}
+		cases = append(cases, pts)
+	}
+	return cases
+}
+
+// Test_Values runs with both ways of computing exact products, which is
+// the same before Go 1.14.
+func Test_Values(t *testing.T) {
+	for _, fma := range []bool{false, true} {
+		restore := robust.UseFMAProducts(fma)
+		testValues(t)
+		restore()
+	}
+}
+
+func testValues(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, tt := range []struct {
+		label     string
+		dim, n    int
+		lift      bool
+		value     func(p [][]float64) float64
+		expansion func(p [][]float64) []float64
+		predicate func(p [][]float64) float64
+	}{
+		{
+			"orient2", 2, 3, false,
+			func(p [][]float64) float64 { return robust.Orient2Value(p[0], p[1], p[2]) },
+			func(p [][]float64) []float64 { return robust.Orient2Expansion(p[0], p[1], p[2]) },
+			func(p [][]float64) float64 { return robust.Orient2(p[0], p[1], p[2]) },
+		},
+		{
+			"orient3", 3, 4, false,
+			func(p [][]float64) float64 { return robust.Orient3Value(p[0], p[1], p[2], p[3]) },
+			func(p [][]float64) []float64 { return robust.Orient3Expansion(p[0], p[1], p[2], p[3]) },
+			func(p [][]float64) float64 { return robust.Orient3(p[0], p[1], p[2], p[3]) },
+		},
+		{
+			"incircle", 2, 4, true,
+			func(p [][]float64) float64 { return robust.InCircleValue(p[0], p[1], p[2], p[3]) },
+			func(p [][]float64) []float64 { return robust.InCircleExpansion(p[0], p[1], p[2], p[3]) },
+			func(p [][]float64) float64 { return robust.InCircle(p[0], p[1], p[2], p[3]) },
+		},
+		{
+			"insphere", 3, 5, true,
+			func(p [][]float64) float64 { return robust.InSphereValue(p[0], p[1], p[2], p[3], p[4]) },
+			func(p [][]float64) []float64 { return robust.InSphereExpansion(p[0], p[1], p[2], p[3], p[4]) },
+			func(p [][]float64) float64 { return robust.InSphere(p[0], p[1], p[2], p[3], p[4]) },
+		},
+	} {
+		t.Run(tt.label, func(t *testing.T) {
+			for i, p := range valueCases(rng, tt.dim, tt.n) {
+				want := ratDet(p[len(p)-1], tt.lift, p[:len(p)-1]...)
+
+				sum := new(big.Rat)
+				for _, c := range tt.expansion(p) {
+					sum.Add(sum, new(big.Rat).SetFloat64(c))
+				}
+				if sum.Cmp(want) != 0 {
+					t.Fatalf("case %d: expansion %s; want %s", i, sum.FloatString(40), want.FloatString(40))
+				}
+
+				rounded, _ := want.Float64()
+				if got := tt.value(p); math.Float64bits(got) != math.Float64bits(rounded) {
+					t.Fatalf("case %d: want %g; got %g", i, rounded, got)
+				}
+				if got := tt.predicate(p); sign(got) != want.Sign() {
+					t.Fatalf("case %d: predicate %g; want sign %d", i, got, want.Sign())
+				}
+			}
+		})
+	}
+}
+
+func Benchmark_Orient2Value(b *testing.B) {
+	fixtures := loadCases(b, "orient2.txt", 6)
+	for n := 0; n < b.N; n++ {
+		for _, tt := range fixtures {
+			result = robust.Orient2Value(tt.args[0:2], tt.args[2:4], tt.args[4:6])
+		}
+	}
+}
@@ -0,0 +1,357 @@
+// Package voronoi extracts the Voronoi diagram dual to a Delaunay
+// triangulation. The triangulation is verified edge by edge with the
+// exact `robust.InCircle` predicate before the dual is built, and
+// Voronoi vertices that coincide because their triangles are cocircular
+// are merged using the same exact test.
+//
+// Sites are given as a flat `[]float64` buffer of XY pairs and triangles
+// as a flat `[]int32` buffer of counterclockwise index triples into it.
+package voronoi
+
+import (
+	"errors"
+	"math"
+
+	robust "neilpa.me/cgo-shewchuk-robust"
+)
+
+var (
+	// ErrInvalid is returned for malformed input buffers, out of range
+	// indices or edges shared by more than two triangles.
+	ErrInvalid = errors.New("voronoi: invalid triangulation")
+	// ErrOrientation is returned when a triangle is not strictly
+	// counterclockwise according to `robust.Orient2`.
+	ErrOrientation = errors.New("voronoi: triangle is not counterclockwise")
+	// ErrNotDelaunay is returned when an interior edge fails the exact
+	// `robust.InCircle` test.
+	ErrNotDelaunay = errors.New("voronoi: triangulation is not Delaunay")
+)
+
+// Diagram is the Voronoi diagram of a set of sites.
+type Diagram struct {
+	// Vertices is a flat buffer of XY Voronoi vertices. Each is the
+	// circumcenter of one or more (cocircular) Delaunay triangles.
+	Vertices []float64
+	// Errors holds the absolute error bound of each Voronoi vertex as
+	// reported by `Circumcenter`.
+	Errors []float64
+	// Cells holds the Voronoi cell of each site, in site order.
+	Cells []Cell
+}
+
+// Cell is the Voronoi region of a single site.
+type Cell struct {
+	// Vertices indexes Diagram.Vertices in counterclockwise order. It's
+	// empty for sites that aren't referenced by any triangle.
+	Vertices []int
+	// Unbounded is true for sites on the convex hull. Their cells are
+	// open polygonal chains closed off by two rays.
+	Unbounded bool
+	// Rays holds the outward directions of the unbounded edges leaving
+	// the first and last vertex respectively. Only set when Unbounded.
+	Rays [2][2]float64
+}
+
+// edge is a directed triangulation edge.
+type edge struct{ a, b int32 }
+
+// New builds the Voronoi diagram of the sites from their Delaunay
+// triangulation. Every triangle must be counterclockwise and every
+// interior edge must be locally Delaunay, which is verified exactly.
+//
+// Interior edges whose four points are exactly cocircular produce the
+// same Voronoi vertex for both triangles, so such vertices are merged
+// and the zero-length Voronoi edges between them are dropped.
+func New(sites []float64, tris []int32) (*Diagram, error) {
+	if len(sites)%2 != 0 || len(tris)%3 != 0 {
+		return nil, ErrInvalid
+	}
+	nsites := int32(len(sites) / 2)
+	ntris := len(tris) / 3
+	site := func(i int32) []float64 { return sites[2*i : 2*i+2] }
+
+	// Map each directed edge to the triangle on its left
+	owner := make(map[edge]int, len(tris))
+	for t := 0; t < ntris; t++ {
+		a, b, c := tris[3*t], tris[3*t+1], tris[3*t+2]
+		if a < 0 || b < 0 || c < 0 || a >= nsites || b >= nsites || c >= nsites {
+			return nil, ErrInvalid
+		}
+		if robust.Orient2(site(a), site(b), site(c)) <= 0 {
+			return nil, ErrOrientation
+		}
+		for _, e := range [3]edge{{a, b}, {b, c}, {c, a}} {
+			if _, ok := owner[e]; ok {
+				return nil, ErrInvalid
+			}
+			owner[e] = t
+		}
+	}
+
+	// Verify interior edges and union cocircular triangles
+	groups := newUnionFind(ntris)
+	for e, t := range owner {
+		u, ok := owner[edge{e.b, e.a}]
+		if !ok || u < t {
+			continue // hull edge or already visited from the other side
+		}
+		apex := opposite(tris, t, e)
+		other := opposite(tris, u, edge{e.b, e.a})
+		det := robust.InCircle(site(e.a), site(e.b), site(apex), site(other))
+		if det > 0 {
+			return nil, ErrNotDelaunay
+		}
+		if det == 0 {
+			groups.union(t, u)
+		}
+	}
+
+	// One Voronoi vertex per group, computed from its representative
+	// (smallest) triangle so that merged vertices are reproducible.
+	d := &Diagram{Cells: make([]Cell, nsites)}
+	vertex := make([]int, ntris)
+	index := make(map[int]int)
+	for t := 0; t < ntris; t++ {
+		r := groups.find(t)
+		v, ok := index[r]
+		if !ok {
+			a, b, c := tris[3*r], tris[3*r+1], tris[3*r+2]
+			x, y, err := Circumcenter(site(a), site(b), site(c))
+			v = len(d.Errors)
+			index[r] = v
+			d.Vertices = append(d.Vertices, x, y)
+			d.Errors = append(d.Errors, err)
+		}
+		vertex[t] = v
+	}
+
+	// Walk the triangle fan around each site in counterclockwise order
+	start := make([]int, nsites)
+	for i := range start {
+		start[i] = -1
+	}
+	for t := 0; t < ntris; t++ {
+		for k := 0; k < 3; k++ {
+			a, b := tris[3*t+k], tris[3*t+(k+1)%3]
+			if start[a] < 0 {
+				start[a] = t
+			}
+			if _, ok := owner[edge{b, a}]; !ok {
+				start[a] = t // hull edge leaving the site begins the fan
+			}
+		}
+	}
+	for s := int32(0); s < nsites; s++ {
+		if start[s] < 0 {
+			continue
+		}
+		cell := &d.Cells[s]
+		t := start[s]
+		first := next(tris, t, s)
+		if _, ok := owner[edge{first, s}]; !ok {
+			cell.Unbounded = true
+			cell.Rays[0] = outward(site(s), site(first))
+		}
+		for {
+			v := vertex[t]
+			if n := len(cell.Vertices); n == 0 || cell.Vertices[n-1] != v {
+				cell.Vertices = append(cell.Vertices, v)
+			}
+			last := prev(tris, t, s)
+			u, ok := owner[edge{s, last}]
+			if !ok {
+				cell.Rays[1] = outward(site(last), site(s))
+				break
+			}
+			if u == start[s] {
+				break
+			}
+			t = u
+		}
+		if n := len(cell.Vertices); !cell.Unbounded && n > 1 && cell.Vertices[0] == cell.Vertices[n-1] {
+			cell.Vertices = cell.Vertices[:n-1]
+		}
+	}
+	return d, nil
+}
+
+// Clip returns the Voronoi cell of site i intersected with the axis
+// aligned box [min, max] as a flat buffer of XY pairs in counterclockwise
+// order. The result is empty if the cell misses the box entirely.
+//
+// Clipping is done in floating point on the computed Voronoi vertices,
+// so the result inherits their error bounds.
+func (d *Diagram) Clip(i int, min, max [2]float64) []float64 {
+	cell := d.Cells[i]
+	if len(cell.Vertices) == 0 {
+		return nil
+	}
+	poly := make([]float64, 0, 2*len(cell.Vertices)+4)
+	for _, v := range cell.Vertices {
+		poly = append(poly, d.Vertices[2*v], d.Vertices[2*v+1])
+	}
+	if cell.Unbounded {
+		// Extend both rays well past the box. Hull cells are convex
+		// and their rays diverge, so closing the chord between the
+		// far ends keeps everything inside the box that's in the cell.
+		cx, cy := (min[0]+max[0])/2, (min[1]+max[1])/2
+		far := math.Hypot(max[0]-min[0], max[1]-min[1])
+		for j := 0; j < len(poly); j += 2 {
+			far = math.Max(far, math.Hypot(poly[j]-cx, poly[j+1]-cy))
+		}
+		far *= 4
+		n := len(poly)
+		head := []float64{poly[0] + far*cell.Rays[0][0], poly[1] + far*cell.Rays[0][1]}
+		poly = append(head, poly...)
+		poly = append(poly, poly[n]+far*cell.Rays[1][0], poly[n+1]+far*cell.Rays[1][1])
+	}
+	return clipBox(poly, min, max)
+}
+
+// Circumcenter returns the center of the circle passing through the
+// points a, b, and c along with an absolute error bound on each of the
+// computed coordinates. The bound follows from a forward error analysis
+// of the formula evaluated relative to a, and is +Inf when the points
+// are too close to collinear for the computed denominator to be trusted.
+//
+// Each slice parameter must contain at least 2 values.
+func Circumcenter(a, b, c []float64) (x, y, errbound float64) {
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	blift := bx*bx + by*by
+	clift := cx*cx + cy*cy
+
+	bxcy, bycx := bx*cy, by*cx
+	denom := 2 * (bxcy - bycx)
+	numx := cy*blift - by*clift
+	numy := bx*clift - cx*blift
+
+	// The lifts carry 4 roundings and the products 3, so each numerator
+	// is within gamma(7) of its permanent and the denominator gamma(4).
+	errd := gamma(4) * 2 * (math.Abs(bxcy) + math.Abs(bycx))
+	errx := gamma(7) * (math.Abs(cy)*blift + math.Abs(by)*clift)
+	erry := gamma(7) * (math.Abs(bx)*clift + math.Abs(cx)*blift)
+	mag := math.Abs(denom) - errd
+	if !(mag > 0) {
+		return a[0] + numx/denom, a[1] + numy/denom, math.Inf(1)
+	}
+
+	// |n'/d' - n/d| <= (en + |n/d| ed) / |d'| with |n/d| <= (|n'|+en)/(|d'|-ed)
+	ux, uy := numx/denom, numy/denom
+	ex := (errx + (math.Abs(numx)+errx)/mag*errd) / math.Abs(denom)
+	ey := (erry + (math.Abs(numy)+erry)/mag*errd) / math.Abs(denom)
+	x, y = a[0]+ux, a[1]+uy
+
+	// Division and the final translation each round once more, and the
+	// bound itself is evaluated in floating point so inflate slightly.
+	ex += epsilon * (math.Abs(ux) + math.Abs(x))
+	ey += epsilon * (math.Abs(uy) + math.Abs(y))
+	errbound = math.Max(ex, ey) * (1 + 16*epsilon)
+	return x, y, errbound
+}
+
+// epsilon is 2^-53, the unit roundoff used by `predicates.c`.
+const epsilon = 1.0 / (1 << 53)
+
+// gamma is the classic n*eps/(1-n*eps) accumulated rounding bound.
+func gamma(n float64) float64 {
+	return n * epsilon / (1 - n*epsilon)
+}
+
+// outward is the unit normal to the right of the directed hull edge p->q.
+func outward(p, q []float64) [2]float64 {
+	dx, dy := q[0]-p[0], q[1]-p[1]
+	l := math.Hypot(dx, dy)
+	return [2]float64{dy / l, -dx / l}
+}
+
+// opposite returns the vertex of triangle t not on the edge e.
+func opposite(tris []int32, t int, e edge) int32 {
+	for _, v := range tris[3*t : 3*t+3] {
+		if v != e.a && v != e.b {
+			return v
+		}
+	}
+	return -1
+}
+
+// next returns the vertex following s in triangle t.
+func next(tris []int32, t int, s int32) int32 {
+	for k := 0; k < 3; k++ {
+		if tris[3*t+k] == s {
+			return tris[3*t+(k+1)%3]
+		}
+	}
+	return -1
+}
+
+// prev returns the vertex preceding s in triangle t.
+func prev(tris []int32, t int, s int32) int32 {
+	for k := 0; k < 3; k++ {
+		if tris[3*t+k] == s {
+			return tris[3*t+(k+2)%3]
+		}
+	}
+	return -1
+}
+
+// clipBox clips a convex polygon to an axis aligned box with the
+// Sutherland-Hodgman algorithm.
+func clipBox(poly []float64, min, max [2]float64) []float64 {
+	for axis := 0; axis < 2; axis++ {
+		poly = clipHalf(poly, axis, min[axis], 1)
+		poly = clipHalf(poly, axis, max[axis], -1)
+	}
+	return poly
+}
+
+// clipHalf keeps the part of poly where dir*(p[axis]-v) >= 0.
+func clipHalf(poly []float64, axis int, v, dir float64) []float64 {
+	n := len(poly) / 2
+	if n == 0 {
+		return nil
+	}
+	var out []float64
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		p, q := poly[2*i:2*i+2], poly[2*j:2*j+2]
+		dp, dq := dir*(p[axis]-v), dir*(q[axis]-v)
+		if dp >= 0 {
+			out = append(out, p[0], p[1])
+		}
+		if (dp < 0 && dq > 0) || (dp > 0 && dq < 0) {
+			t := dp / (dp - dq)
+			out = append(out, p[0]+t*(q[0]-p[0]), p[1]+t*(q[1]-p[1]))
+		}
+	}
+	return out
+}
+
+// unionFind is a minimal disjoint set over triangle indices that keeps
+// the smallest index as each set's representative.
+type unionFind []int
+
+func newUnionFind(n int) unionFind {
+	u := make(unionFind, n)
+	for i := range u {
+		u[i] = i
+	}
+	return u
+}
+
+func (u unionFind) find(i int) int {
+	for u[i] != i {
+		u[i] = u[u[i]]
+		i = u[i]
+	}
+	return i
+}
+
+func (u unionFind) union(i, j int) {
+	i, j = u.find(i), u.find(j)
+	if i < j {
+		u[j] = i
+	} else {
+		u[i] = j
+	}
+}
@@ -0,0 +1,121 @@
+package voronoi_test
+
+import (
+	"math"
+	"testing"
+
+	"neilpa.me/cgo-shewchuk-robust/voronoi"
+)
+
+func Test_Circumcenter(t *testing.T) {
+	tests := []struct {
+		label   string
+		a, b, c []float64
+		x, y    float64
+	}{
+		{"right", []float64{0, 0}, []float64{2, 0}, []float64{0, 2}, 1, 1},
+		{"offset", []float64{1e6, 1e6}, []float64{1e6 + 2, 1e6}, []float64{1e6, 1e6 + 2}, 1e6 + 1, 1e6 + 1},
+		{"obtuse", []float64{0, 0}, []float64{4, 0}, []float64{2, 1}, 2, -1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			x, y, err := voronoi.Circumcenter(tt.a, tt.b, tt.c)
+			if math.Abs(x-tt.x) > err || math.Abs(y-tt.y) > err {
+				t.Errorf("want: (%g, %g); got: (%g, %g) ± %g", tt.x, tt.y, x, y, err)
+			}
+			if err > 1e-9 {
+				t.Errorf("loose error bound %g", err)
+			}
+		})
+	}
+
+	_, _, err := voronoi.Circumcenter([]float64{0, 0}, []float64{1, 1}, []float64{2, 2})
+	if !math.IsInf(err, 1) {
+		t.Errorf("collinear: want: +Inf; got: %g", err)
+	}
+}
+
+func Test_New(t *testing.T) {
+	// Square fanned around its center site, giving one closed cell.
+	sites := []float64{0, 0, 2, 0, 2, 2, 0, 2, 1, 1}
+	tris := []int32{0, 1, 4, 1, 2, 4, 2, 3, 4, 3, 0, 4}
+
+	d, err := voronoi.New(sites, tris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(d.Vertices) / 2; got != 4 {
+		t.Fatalf("vertices: want: 4; got: %d", got)
+	}
+
+	center := d.Cells[4]
+	if center.Unbounded || len(center.Vertices) != 4 {
+		t.Errorf("center cell: got: %+v", center)
+	}
+	for i := 0; i < 4; i++ {
+		cell := d.Cells[i]
+		if !cell.Unbounded || len(cell.Vertices) != 2 {
+			t.Errorf("corner cell %d: got: %+v", i, cell)
+		}
+	}
+
+	clip := d.Clip(4, [2]float64{0, 0}, [2]float64{2, 2})
+	if area := polygonArea(clip); math.Abs(area-2) > 1e-12 {
+		t.Errorf("center area: want: 2; got: %g", area)
+	}
+	total := 0.0
+	for i := range d.Cells {
+		total += polygonArea(d.Clip(i, [2]float64{-1, -1}, [2]float64{3, 3}))
+	}
+	if math.Abs(total-16) > 1e-9 {
+		t.Errorf("total area: want: 16; got: %g", total)
+	}
+}
+
+func Test_NewCocircular(t *testing.T) {
+	sites := []float64{0, 0, 1, 0, 1, 1, 0, 1}
+	tris := []int32{0, 1, 2, 0, 2, 3}
+
+	d, err := voronoi.New(sites, tris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Vertices) != 2 || d.Vertices[0] != 0.5 || d.Vertices[1] != 0.5 {
+		t.Fatalf("vertices: want: [0.5 0.5]; got: %v", d.Vertices)
+	}
+	for i, cell := range d.Cells {
+		if !cell.Unbounded || len(cell.Vertices) != 1 {
+			t.Errorf("cell %d: got: %+v", i, cell)
+		}
+	}
+}
+
+func Test_NewErrors(t *testing.T) {
+	tests := []struct {
+		label string
+		sites []float64
+		tris  []int32
+		err   error
+	}{
+		{"clockwise", []float64{0, 0, 1, 0, 0, 1}, []int32{0, 2, 1}, voronoi.ErrOrientation},
+		{"range", []float64{0, 0, 1, 0, 0, 1}, []int32{0, 1, 3}, voronoi.ErrInvalid},
+		{"delaunay", []float64{0, 0, 4, 0, 4, 1, 0, 1.5}, []int32{0, 1, 3, 1, 2, 3}, voronoi.ErrNotDelaunay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			if _, err := voronoi.New(tt.sites, tt.tris); err != tt.err {
+				t.Errorf("want: %v; got: %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func polygonArea(poly []float64) float64 {
+	n := len(poly) / 2
+	area := 0.0
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		area += poly[2*i]*poly[2*j+1] - poly[2*j]*poly[2*i+1]
+	}
+	return area / 2
+}